@@ -0,0 +1,81 @@
+package openhours
+
+import "time"
+
+// Coverage returns how many of os are open at the start of each slot of resolution of the week of at, from the sunday midnight
+// before it on the wall clock (eg: how many stores are open at each quarter of an hour), the last slot being cut at the end of the week.
+// The open hours depending on the date are read on the dates of that week. It returns nil if resolution is not positive.
+func Coverage(at time.Time, resolution time.Duration, os ...OpenHours) []int {
+	if resolution <= 0 {
+		return nil
+	}
+	const week = 7 * 24 * time.Hour
+	slots := int((week + resolution - 1) / resolution)
+	diff := make([]int, slots+1)
+	year, month, day := at.Date()
+	day -= int(at.Weekday())
+	for _, o := range os {
+		loc := o.location()
+		if o.daily() {
+			for i := 0; i < slots; i++ {
+				if o.Match(time.Date(year, month, day, 0, 0, 0, int(time.Duration(i)*resolution), loc)) {
+					diff[i]++
+					diff[i+1]--
+				}
+			}
+			continue
+		}
+		w := fold(inLocation(o.week, loc), loc)
+		offset := weekZone(w)
+		for i := 0; i < len(w)-1; i += 2 {
+			from, to := dateOffset(w[i], offset), dateOffset(w[i+1], offset)
+			// the slots starting in the window
+			diff[(from+resolution-1)/resolution]++
+			diff[(to+resolution-1)/resolution]--
+		}
+	}
+	counts := make([]int, slots)
+	for i, n := 0, 0; i < slots; i++ {
+		n += diff[i]
+		counts[i] = n
+	}
+	return counts
+}
+
+// AlwaysCoveredBy returns the open hours open when at least one of os is open, in the location of the first one.
+// It is closed without any open hours. Like Union, it returns ErrDated for more than one open hours depending on the date.
+func AlwaysCoveredBy(os ...OpenHours) (OpenHours, error) {
+	if len(os) == 0 {
+		return OpenHours{week: []time.Time{}, loc: time.UTC}, nil
+	}
+	o := os[0]
+	if !o.dated() {
+		o = o.combine(OpenHours{}, func(a, _ bool) bool { return a })
+	}
+	for _, other := range os[1:] {
+		var err error
+		if o, err = o.Union(other); err != nil {
+			return OpenHours{}, err
+		}
+	}
+	return o, nil
+}
+
+// CommonHours returns the open hours open when all of os are open, in the location of the first one.
+// It is always open without any open hours. Like Intersect, it returns ErrDated for open hours depending on the date.
+func CommonHours(os ...OpenHours) (OpenHours, error) {
+	if len(os) == 0 {
+		return OpenHours{}.Complement()
+	}
+	if os[0].dated() {
+		return OpenHours{}, ErrDated
+	}
+	o := os[0].combine(OpenHours{}, func(a, _ bool) bool { return a })
+	for _, other := range os[1:] {
+		var err error
+		if o, err = o.Intersect(other); err != nil {
+			return OpenHours{}, err
+		}
+	}
+	return o, nil
+}
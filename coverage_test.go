@@ -0,0 +1,78 @@
+package openhours
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCoverage(t *testing.T) {
+	stores := []OpenHours{
+		NewMust("mo-fr 09:00-17:00", l),
+		NewMust("mo-sa 10:00-19:00", l),
+		NewMust("sa 22:00-02:00", l),
+		NewMust("jun-aug mo 08:00-12:00", l),
+	}
+	got := Coverage(time.Date(2019, 1, 9, 0, 0, 0, 0, l), time.Hour, stores...)
+	if len(got) != 7*24 {
+		t.Fatalf("len(Coverage()) = %v, want %v", len(got), 7*24)
+	}
+	tests := []struct {
+		day, hour, want int
+	}{
+		{0, 0, 1}, {0, 1, 1}, {0, 2, 0},
+		{1, 8, 0}, {1, 9, 1}, {1, 10, 2}, {1, 16, 2}, {1, 17, 1}, {1, 19, 0},
+		{6, 9, 0}, {6, 10, 1}, {6, 22, 1}, {6, 23, 1},
+	}
+	for _, tt := range tests {
+		if n := got[tt.day*24+tt.hour]; n != tt.want {
+			t.Errorf("Coverage()[day %v, %02d:00] = %v, want %v", tt.day, tt.hour, n, tt.want)
+		}
+	}
+	if got := Coverage(time.Date(2019, 1, 9, 0, 0, 0, 0, l), 90*time.Minute, NewMust("mo 01:00-02:00", l)); len(got) != 112 || got[16] != 0 || got[17] != 1 || got[18] != 0 {
+		t.Errorf("Coverage() = %v", got)
+	}
+	summer := Coverage(time.Date(2019, 6, 12, 0, 0, 0, 0, l), time.Hour, stores...)
+	for _, tt := range []struct{ day, hour, want int }{{1, 7, 0}, {1, 8, 1}, {1, 9, 2}, {1, 11, 3}, {1, 12, 2}, {2, 8, 0}} {
+		if n := summer[tt.day*24+tt.hour]; n != tt.want {
+			t.Errorf("Coverage() in june [day %v, %02d:00] = %v, want %v", tt.day, tt.hour, n, tt.want)
+		}
+	}
+	if got := Coverage(time.Time{}, 0, stores...); got != nil {
+		t.Errorf("Coverage() = %v, want nil", got)
+	}
+}
+
+func TestAlwaysCoveredBy(t *testing.T) {
+	tests := []struct {
+		name string
+		got  OpenHours
+		want string
+	}{
+		{"union", must(AlwaysCoveredBy(NewMust("mo-fr 09:00-12:00", l), NewMust("mo-fr 11:00-17:00", l), NewMust("sa 10:00-12:00", l))), "mo-fr 09:00-17:00; sa 10:00-12:00"},
+		{"intersection", must(CommonHours(NewMust("mo-su 08:00-20:00", l), NewMust("mo-fr 09:00-17:00", l), NewMust("fr-sa 12:00-22:00", l))), "fr 12:00-17:00"},
+		{"one", must(AlwaysCoveredBy(NewMust("sa 22:00-02:00", l))), "su 00:00-02:00; sa 22:00-24:00"},
+		{"no union", must(AlwaysCoveredBy()), "su off"},
+		{"no intersection", must(CommonHours()), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if want := NewMust(tt.want, tt.got.location()); !tt.got.Equal(want) {
+				t.Errorf("got %v, want %v", tt.got.String(), want.String())
+			}
+		})
+	}
+	dated := NewMust("Jun-Aug Mo 08:00-12:00", l)
+	if got, err := AlwaysCoveredBy(NewMust("mo 09:00-17:00", l), dated); err != nil || !got.Match(time.Date(2019, 6, 10, 8, 30, 0, 0, l)) || got.Match(time.Date(2019, 1, 7, 8, 30, 0, 0, l)) {
+		t.Errorf("AlwaysCoveredBy() = %v, %v", got.OSMString(), err)
+	}
+	if _, err := AlwaysCoveredBy(dated, dated); err != ErrDated {
+		t.Errorf("AlwaysCoveredBy() error = %v, want %v", err, ErrDated)
+	}
+	if _, err := CommonHours(NewMust("mo 09:00-17:00", l), dated); err != ErrDated {
+		t.Errorf("CommonHours() error = %v, want %v", err, ErrDated)
+	}
+	if got := must(AlwaysCoveredBy(NewMust("mo 09:00-12:00", l))); !reflect.DeepEqual(got, NewMust("mo 09:00-12:00", l)) {
+		t.Errorf("AlwaysCoveredBy() = %v, want mo 09:00-12:00", got.String())
+	}
+}
@@ -0,0 +1,228 @@
+package openhours
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ChangeKind is the kind of change of a window found by Diff
+type ChangeKind int
+
+const (
+	ChangeAdded     ChangeKind = iota + 1 // the window is new: "Mo 10:00-12:00" to "Mo 10:00-12:00,14:00-16:00"
+	ChangeRemoved                         // the window is gone: "Mo 10:00-12:00" to "off"
+	ChangeExtended                        // the window is longer: "Fr 10:00-20:00" to "Fr 10:00-22:00"
+	ChangeShortened                       // the window is shorter: "Fr 10:00-20:00" to "Fr 12:00-20:00"
+)
+
+var changeKinds = map[ChangeKind]string{
+	ChangeAdded:     "added",
+	ChangeRemoved:   "removed",
+	ChangeExtended:  "extended",
+	ChangeShortened: "shortened",
+}
+
+func (k ChangeKind) String() string {
+	if str, exist := changeKinds[k]; exist {
+		return str
+	}
+	return fmt.Sprintf("ChangeKind(%d)", int(k))
+}
+
+// Change is a window of a week day which is not the same in two open hours,
+// or a rule depending on the date or an exception added or removed
+type Change struct {
+	Day  time.Weekday
+	Kind ChangeKind
+	Old  *Window // nil if the window is added
+	New  *Window // nil if the window is removed
+	Rule string  // the rule ("Dec 25 off", "PH 10:00-14:00") or exception ("2019-12-24 09:00-14:00") if any, Day, Old and New being unset
+}
+
+func (c Change) String() string {
+	switch {
+	case c.Rule != "":
+		return fmt.Sprintf("%v %v", c.Rule, c.Kind)
+	case c.Old == nil:
+		return fmt.Sprintf("%v %v-%v %v", c.Day, c.New.Open, c.New.Close, c.Kind)
+	case c.New == nil:
+		return fmt.Sprintf("%v %v-%v %v", c.Day, c.Old.Open, c.Old.Close, c.Kind)
+	}
+	return fmt.Sprintf("%v %v-%v %v to %v-%v", c.Day, c.Old.Open, c.Old.Close, c.Kind, c.New.Open, c.New.Close)
+}
+
+// Diff returns the changes of the windows of each week day from before to after, from monday, read on the wall clock like Union.
+// A window overlapping a single window of the other open hours is extended or shortened,
+// the other ones are removed and added. For open hours depending on the date, the week days compare the rules
+// applying to every date, then the other rules which are not in both are removed and added, then the exceptions by date.
+func Diff(before, after OpenHours) []Change {
+	before, after = before.WithLocation(time.UTC), after.WithLocation(time.UTC)
+	olds, news := OpenHours{week: before.weekly()}.dayPairs(), OpenHours{week: after.weekly()}.dayPairs()
+	changes := []Change{}
+	for _, day := range []int{1, 2, 3, 4, 5, 6, 0} {
+		changes = append(changes, diffDay(day, olds[day], news[day])...)
+	}
+	oldRules, newRules := before.datedRules(), after.datedRules()
+	for _, r := range without(oldRules, newRules) {
+		changes = append(changes, Change{Kind: ChangeRemoved, Rule: r})
+	}
+	for _, r := range without(newRules, oldRules) {
+		changes = append(changes, Change{Kind: ChangeAdded, Rule: r})
+	}
+	dates := []Date{}
+	for date := range before.ex {
+		dates = append(dates, date)
+	}
+	for date := range after.ex {
+		if _, exist := before.ex[date]; !exist {
+			dates = append(dates, date)
+		}
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].String() < dates[j].String() })
+	for _, date := range dates {
+		old, hadOld := before.ex[date]
+		ex, hasNew := after.ex[date]
+		if hadOld && hasNew && formatException(old) == formatException(ex) {
+			continue
+		}
+		if hadOld {
+			changes = append(changes, Change{Kind: ChangeRemoved, Rule: date.String() + " " + formatException(old)})
+		}
+		if hasNew {
+			changes = append(changes, Change{Kind: ChangeAdded, Rule: date.String() + " " + formatException(ex)})
+		}
+	}
+	return changes
+}
+
+// weekly returns the generic week of the rules applying to every date, the ones not restricted to dates or holidays
+func (o OpenHours) weekly() []time.Time {
+	if o.rules == nil {
+		return o.week
+	}
+	week := []time.Time{}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		w := []time.Time{}
+		for _, r := range o.rules {
+			if len(r.dates) > 0 || r.ph || r.sh {
+				continue
+			}
+			pairs := []time.Time{}
+			offset := weekZone(r.week)
+			for i := 0; i < len(r.week)-1; i += 2 {
+				if startsOn(dateOffset(r.week[i], offset), day) {
+					pairs = append(pairs, r.week[i], r.week[i+1])
+				}
+			}
+			if r.off {
+				w = subtract(merge(w), pairs)
+				continue
+			}
+			w = append(w, pairs...)
+		}
+		week = append(week, merge(w)...)
+	}
+	return merge(week)
+}
+
+// datedRules returns the rules restricted to dates or holidays as written by OSMString
+func (o OpenHours) datedRules() []string {
+	rules := []string{}
+	for _, r := range o.rules {
+		if len(r.dates) > 0 || r.ph || r.sh {
+			rules = append(rules, OpenHours{rules: []rule{r}}.osmRules())
+		}
+	}
+	return rules
+}
+
+// without returns the elements of a which are not in b, each element of b being used once
+func without(a, b []string) []string {
+	left := map[string]int{}
+	for _, s := range b {
+		left[s]++
+	}
+	res := []string{}
+	for _, s := range a {
+		if left[s] > 0 {
+			left[s]--
+			continue
+		}
+		res = append(res, s)
+	}
+	return res
+}
+
+// diffDay returns the changes from the windows a to the windows b of day
+func diffDay(day int, a, b [][2]time.Time) []Change {
+	overlaps := func(p, q [2]time.Time) bool {
+		return p[0].Before(q[1]) && q[0].Before(p[1])
+	}
+	countA, countB := make([]int, len(a)), make([]int, len(b))
+	match := make([]int, len(a)) // the window of b overlapping the window of a
+	for i, p := range a {
+		for j, q := range b {
+			if overlaps(p, q) {
+				countA[i]++
+				countB[j]++
+				match[i] = j
+			}
+		}
+	}
+	type change struct {
+		Change
+		at time.Time
+	}
+	changes := []change{}
+	add := func(kind ChangeKind, p, q *[2]time.Time) {
+		c := change{Change: Change{Day: time.Weekday(day), Kind: kind}}
+		if p != nil {
+			w := newWindow(day, *p)
+			c.Old, c.at = &w, p[0]
+		}
+		if q != nil {
+			w := newWindow(day, *q)
+			c.New, c.at = &w, q[0]
+		}
+		changes = append(changes, c)
+	}
+	paired := make([]bool, len(b))
+	for i := range a {
+		if countA[i] != 1 || countB[match[i]] != 1 {
+			continue
+		}
+		p, q := a[i], b[match[i]]
+		paired[match[i]] = true
+		lenA, lenB := p[1].Sub(p[0]), q[1].Sub(q[0])
+		switch {
+		case p[0].Equal(q[0]) && p[1].Equal(q[1]):
+		case lenB > lenA:
+			add(ChangeExtended, &p, &q)
+		case lenB < lenA:
+			add(ChangeShortened, &p, &q)
+		default: // moved
+			add(ChangeRemoved, &p, nil)
+			add(ChangeAdded, nil, &q)
+		}
+		countA[i] = -1
+	}
+	for i := range a {
+		if countA[i] >= 0 {
+			add(ChangeRemoved, &a[i], nil)
+		}
+	}
+	for j := range b {
+		if !paired[j] {
+			add(ChangeAdded, nil, &b[j])
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].at.Before(changes[j].at)
+	})
+	res := make([]Change, len(changes))
+	for i, c := range changes {
+		res[i] = c.Change
+	}
+	return res
+}
@@ -0,0 +1,52 @@
+package openhours
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		before, after string
+		want          []string
+	}{
+		{"mo-fr 10:00-20:00", "mo-fr 10:00-20:00", []string{}},
+		{"mo-fr 10:00-20:00", "mo,tu,we,th,fr 10:00-15:00,15:00-20:00", []string{}},
+		{"mo-fr 10:00-20:00", "mo-th 10:00-20:00; fr 10:00-22:00", []string{"Friday 10:00-20:00 extended to 10:00-22:00"}},
+		{"mo-fr 10:00-20:00", "mo-fr 10:00-20:00; fr 12:00-20:00 off", []string{"Friday 10:00-20:00 shortened to 10:00-12:00"}},
+		{"mo 10:00-12:00", "mo 10:00-12:00,14:00-16:00; tu 10:00-12:00", []string{"Monday 14:00-16:00 added", "Tuesday 10:00-12:00 added"}},
+		{"mo 10:00-12:00; su 10:00-12:00", "su off", []string{"Monday 10:00-12:00 removed", "Sunday 10:00-12:00 removed"}},
+		{"mo 10:00-12:00", "mo 11:00-13:00", []string{"Monday 10:00-12:00 removed", "Monday 11:00-13:00 added"}},
+		{"mo 09:00-17:00", "mo 09:00-12:00,13:00-17:00", []string{"Monday 09:00-17:00 removed", "Monday 09:00-12:00 added", "Monday 13:00-17:00 added"}},
+		{"sa 20:00-02:00", "sa 20:00-04:00", []string{"Saturday 20:00-02:00 extended to 20:00-04:00"}},
+		{"Mo-Fr 09:00-17:00; PH off", "Mo-Fr 09:00-20:00; PH off", []string{"Monday 09:00-17:00 extended to 09:00-20:00", "Tuesday 09:00-17:00 extended to 09:00-20:00", "Wednesday 09:00-17:00 extended to 09:00-20:00", "Thursday 09:00-17:00 extended to 09:00-20:00", "Friday 09:00-17:00 extended to 09:00-20:00"}},
+		{"Mo-Fr 09:00-17:00; PH off", "Mo-Fr 09:00-17:00; PH 10:00-14:00", []string{"PH off removed", "PH 10:00-14:00 added"}},
+		{"Mo-Fr 09:00-17:00; Dec 25 off", "Mo-Fr 09:00-17:00", []string{"Dec 25 off removed"}},
+		{"Apr-Oct Mo-Fr 09:00-17:00", "Apr-Oct Mo-Fr 09:00-17:00; Sa 10:00-12:00", []string{"Saturday 10:00-12:00 added"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.before+" to "+tt.after, func(t *testing.T) {
+			got := []string{}
+			for _, c := range Diff(NewMust(tt.before, l), NewMust(tt.after, l)) {
+				got = append(got, c.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiff_Exceptions(t *testing.T) {
+	before := must(NewMust("Mo-Fr 09:00-17:00", l).WithExceptions(Exceptions{{2019, time.December, 24}: "09:00-14:00", {2019, time.December, 25}: "off"}))
+	after := must(NewMust("Mo-Fr 09:00-17:00", l).WithExceptions(Exceptions{{2019, time.December, 24}: "09:00-12:00", {2019, time.December, 25}: "off", {2019, time.December, 26}: "off"}))
+	got := []string{}
+	for _, c := range Diff(before, after) {
+		got = append(got, c.String())
+	}
+	want := []string{"2019-12-24 09:00-14:00 removed", "2019-12-24 09:00-12:00 added", "2019-12-26 off added"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %q, want %q", got, want)
+	}
+}
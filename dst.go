@@ -0,0 +1,126 @@
+package openhours
+
+import "time"
+
+// DSTPolicy tells how the windows are read on the days the clock changes, when a time does not exist
+// (eg: 01:30 on the spring forward day in Europe/London) or happens twice (01:30 on the fall back day)
+type DSTPolicy int
+
+const (
+	// DSTShift moves a time which does not exist forward by the length of the gap, like the weekly windows are read,
+	// and uses the first time of the ones happening twice. It is the default.
+	DSTShift DSTPolicy = iota
+	// DSTSkip is DSTShift, but the windows opening or closing at a time which does not exist are closed that day
+	DSTSkip
+	// DSTWallClock keeps the windows open while the clock shows their times: a time which does not exist is the change of offset,
+	// a window opens at the first time of the ones happening twice and closes at the last one
+	DSTWallClock
+	// DSTElapsed opens the windows like DSTShift and keeps them open as long as on a day without a change of offset
+	DSTElapsed
+)
+
+// WithDSTPolicy returns the open hours reading the windows with p on the days the clock changes.
+// The policy is used by Match, NextDur, When, AddOpen, Intervals and the functions based on them.
+func (o OpenHours) WithDSTPolicy(p DSTPolicy) OpenHours {
+	o.dst = p
+	return o
+}
+
+// daily returns true if the windows must be read day by day, for the rules and exceptions or the DST policy
+func (o OpenHours) daily() bool {
+	return o.dated() || o.dst != DSTShift
+}
+
+// wallOn returns the wall clock time of the generic week date d on the day of t, as a date in UTC
+func wallOn(t, d time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day()+weekDay(d)-int(t.Weekday()), d.Hour(), d.Minute(), d.Second(), d.Nanosecond(), time.UTC)
+}
+
+// readings returns the real dates when the clock of loc shows the wall clock time w (a date in UTC), in order,
+// and how many there are: none for a time which does not exist, two for a time happening twice
+func readings(w time.Time, loc *time.Location) ([2]time.Time, int) {
+	dates, n := [2]time.Time{}, 0
+	_, before := w.Add(-24 * time.Hour).In(loc).Zone()
+	_, after := w.Add(24 * time.Hour).In(loc).Zone()
+	if before == after { // no change of offset around w
+		dates[0] = w.Add(-time.Duration(before) * time.Second).In(loc)
+		return dates, 1
+	}
+	for _, offset := range [2]int{before, after} {
+		d := w.Add(-time.Duration(offset) * time.Second).In(loc)
+		if _, o := d.Zone(); o == offset && (n == 0 || !dates[0].Equal(d)) {
+			dates[n] = d
+			n++
+		}
+	}
+	if n == 2 && dates[1].Before(dates[0]) {
+		dates[0], dates[1] = dates[1], dates[0]
+	}
+	return dates, n
+}
+
+// shift returns the real date of the wall clock time w (a date in UTC) which does not exist, moved forward by the length of the gap:
+// w read with the offset before the change. time.Date moves it backward west of UTC.
+func shift(w time.Time, loc *time.Location) time.Time {
+	_, before := w.Add(-24 * time.Hour).In(loc).Zone()
+	return w.Add(-time.Duration(before) * time.Second).In(loc)
+}
+
+// window appends to day the real open and close dates of the window between the wall clock times from and to (dates in UTC)
+func (p DSTPolicy) window(day []time.Time, from, to time.Time, loc *time.Location) []time.Time {
+	if start, end, ok := p.bounds(from, to, loc); ok {
+		return append(day, start, end)
+	}
+	return day
+}
+
+// bounds returns the real open and close dates of the window between the wall clock times from and to (dates in UTC),
+// or false if the window is closed that day
+func (p DSTPolicy) bounds(from, to time.Time, loc *time.Location) (time.Time, time.Time, bool) {
+	switch p {
+	case DSTSkip:
+		if _, n := readings(from, loc); n == 0 {
+			return time.Time{}, time.Time{}, false
+		}
+		if _, n := readings(to, loc); n == 0 {
+			return time.Time{}, time.Time{}, false
+		}
+	case DSTWallClock:
+		return wallClock(from, loc, true), wallClock(to, loc, false), true
+	case DSTElapsed:
+		start := first(from, loc)
+		return start, start.Add(to.Sub(from)), true
+	}
+	return first(from, loc), first(to, loc), true
+}
+
+// contains returns true if t is in the window between the wall clock times from and to (dates in UTC)
+func (p DSTPolicy) contains(from, to time.Time, t probe, loc *time.Location) bool {
+	// a change of offset moves the real dates by a few hours at most, the windows far from t are not read
+	if t.wall.Before(from.Add(-6*time.Hour)) || !t.wall.Before(to.Add(6*time.Hour)) {
+		return false
+	}
+	start, end, ok := p.bounds(from, to, loc)
+	return ok && !t.t.Before(start) && t.t.Before(end)
+}
+
+// first returns the first real date when the clock of loc shows w, or the date shift gives when w does not exist
+func first(w time.Time, loc *time.Location) time.Time {
+	if dates, n := readings(w, loc); n > 0 {
+		return dates[0]
+	}
+	return shift(w, loc)
+}
+
+// wallClock returns the first or last real date when the clock of loc shows w, or the change of offset when w does not exist
+func wallClock(w time.Time, loc *time.Location, first bool) time.Time {
+	dates, n := readings(w, loc)
+	switch {
+	case n == 0: // the date after the gap starts the new offset
+		start, _ := shift(w, loc).ZoneBounds()
+		return start
+	case first:
+		return dates[0]
+	}
+	return dates[n-1]
+}
@@ -0,0 +1,143 @@
+package openhours
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOpenHours_WithDSTPolicy(t *testing.T) {
+	gmt := time.FixedZone("GMT", 0)
+	bst := time.FixedZone("BST", 3600)
+	spring, fall := time.Date(2019, 3, 31, 0, 0, 0, 0, l), time.Date(2019, 10, 27, 0, 0, 0, 0, l)
+	tests := []struct {
+		name   string
+		oh     string
+		policy DSTPolicy
+		day    time.Time
+		want   []Interval
+	}{
+		{"shift gap", "su 01:30-03:00", DSTShift, spring, []Interval{{time.Date(2019, 3, 31, 2, 30, 0, 0, bst), time.Date(2019, 3, 31, 3, 0, 0, 0, bst)}}},
+		{"shift gap closed", "su 01:30-02:30", DSTShift, spring, []Interval{}},
+		{"skip gap", "su 01:30-03:00", DSTSkip, spring, []Interval{}},
+		{"wall clock gap", "su 01:30-02:30", DSTWallClock, spring, []Interval{{time.Date(2019, 3, 31, 2, 0, 0, 0, bst), time.Date(2019, 3, 31, 2, 30, 0, 0, bst)}}},
+		{"elapsed gap", "su 01:30-02:30", DSTElapsed, spring, []Interval{{time.Date(2019, 3, 31, 2, 30, 0, 0, bst), time.Date(2019, 3, 31, 3, 30, 0, 0, bst)}}},
+		{"elapsed spring", "su 00:00-04:00", DSTElapsed, spring, []Interval{{time.Date(2019, 3, 31, 0, 0, 0, 0, gmt), time.Date(2019, 3, 31, 5, 0, 0, 0, bst)}}},
+		{"shift twice", "su 00:30-01:30", DSTShift, fall, []Interval{{time.Date(2019, 10, 27, 0, 30, 0, 0, bst), time.Date(2019, 10, 27, 1, 30, 0, 0, bst)}}},
+		{"skip twice", "su 00:30-01:30", DSTSkip, fall, []Interval{{time.Date(2019, 10, 27, 0, 30, 0, 0, bst), time.Date(2019, 10, 27, 1, 30, 0, 0, bst)}}},
+		{"wall clock twice", "su 00:30-01:30", DSTWallClock, fall, []Interval{{time.Date(2019, 10, 27, 0, 30, 0, 0, bst), time.Date(2019, 10, 27, 1, 30, 0, 0, gmt)}}},
+		{"elapsed fall", "su 00:00-04:00", DSTElapsed, fall, []Interval{{time.Date(2019, 10, 27, 0, 0, 0, 0, bst), time.Date(2019, 10, 27, 3, 0, 0, 0, gmt)}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkDSTDay(t, NewMust(tt.oh, l).WithDSTPolicy(tt.policy), tt.day, tt.want)
+		})
+	}
+}
+
+func TestOpenHours_WithDSTPolicy_Zones(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sydney, err := time.LoadLocation("Australia/Sydney")
+	if err != nil {
+		t.Fatal(err)
+	}
+	est, edt := time.FixedZone("EST", -5*3600), time.FixedZone("EDT", -4*3600)
+	aest, aedt := time.FixedZone("AEST", 10*3600), time.FixedZone("AEDT", 11*3600)
+	spring, fall := time.Date(2019, 3, 10, 0, 0, 0, 0, ny), time.Date(2019, 11, 3, 0, 0, 0, 0, ny)
+	tests := []struct {
+		name   string
+		oh     string
+		loc    *time.Location
+		policy DSTPolicy
+		day    time.Time
+		want   []Interval
+	}{
+		{"shift gap", "su 02:30-04:00", ny, DSTShift, spring, []Interval{{time.Date(2019, 3, 10, 3, 30, 0, 0, edt), time.Date(2019, 3, 10, 4, 0, 0, 0, edt)}}},
+		{"skip gap", "su 02:30-04:00", ny, DSTSkip, spring, []Interval{}},
+		{"wall clock gap", "su 01:30-02:30", ny, DSTWallClock, spring, []Interval{{time.Date(2019, 3, 10, 1, 30, 0, 0, est), time.Date(2019, 3, 10, 3, 0, 0, 0, edt)}}},
+		{"elapsed gap", "su 02:30-03:30", ny, DSTElapsed, spring, []Interval{{time.Date(2019, 3, 10, 3, 30, 0, 0, edt), time.Date(2019, 3, 10, 4, 30, 0, 0, edt)}}},
+		{"shift overnight", "mo-su 20:00-02:00", ny, DSTShift, spring.Add(-12 * time.Hour), []Interval{{time.Date(2019, 3, 9, 20, 0, 0, 0, est), time.Date(2019, 3, 10, 3, 0, 0, 0, edt)}}},
+		{"wall clock twice", "su 00:30-01:30", ny, DSTWallClock, fall, []Interval{{time.Date(2019, 11, 3, 0, 30, 0, 0, edt), time.Date(2019, 11, 3, 1, 30, 0, 0, est)}}},
+		{"shift gap east", "su 02:30-04:00", sydney, DSTShift, time.Date(2019, 10, 6, 0, 0, 0, 0, sydney), []Interval{{time.Date(2019, 10, 6, 3, 30, 0, 0, aedt), time.Date(2019, 10, 6, 4, 0, 0, 0, aedt)}}},
+		{"wall clock gap east", "su 01:30-02:30", sydney, DSTWallClock, time.Date(2019, 10, 6, 0, 0, 0, 0, sydney), []Interval{{time.Date(2019, 10, 6, 1, 30, 0, 0, aest), time.Date(2019, 10, 6, 3, 0, 0, 0, aedt)}}},
+		{"wall clock twice east", "su 02:30-03:30", sydney, DSTWallClock, time.Date(2019, 4, 7, 0, 0, 0, 0, sydney), []Interval{{time.Date(2019, 4, 7, 2, 30, 0, 0, aedt), time.Date(2019, 4, 7, 3, 30, 0, 0, aest)}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkDSTDay(t, NewMust(tt.oh, tt.loc).WithDSTPolicy(tt.policy), tt.day, tt.want)
+		})
+	}
+	// the sundays of january and february open for 1h30, the spring forward day from 03:00 to 04:00
+	o := NewMust("su 02:30-04:00", ny).WithDSTPolicy(DSTWallClock)
+	if got, want := o.OpenBetween(time.Date(2019, 1, 1, 0, 0, 0, 0, ny), time.Date(2019, 3, 11, 0, 0, 0, 0, ny)), 14*time.Hour+30*time.Minute; got != want {
+		t.Errorf("OpenHours.OpenBetween() = %v, want %v", got, want)
+	}
+}
+
+// checkDSTDay checks the windows of o on the day starting at day, and the functions reading them
+func checkDSTDay(t *testing.T, o OpenHours, day time.Time, want []Interval) {
+	t.Helper()
+	got := o.Intervals(day, day.AddDate(0, 0, 1))
+	if !equalIntervals(got, want) {
+		t.Fatalf("OpenHours.Intervals() = %v, want %v", got, want)
+	}
+	if len(want) == 0 {
+		return
+	}
+	loc := o.location()
+	w, before := Interval{want[0].Start.In(loc), want[0].End.In(loc)}, day.Add(-time.Minute)
+	if isOpen, next := o.NextDate(before); isOpen || !next.Equal(w.Start) {
+		t.Errorf("OpenHours.NextDate() = %v, %v, want false, %v", isOpen, next, w.Start)
+	}
+	if isOpen, next := o.NextDate(w.Start); !isOpen || !next.Equal(w.End) {
+		t.Errorf("OpenHours.NextDate() = %v, %v, want true, %v", isOpen, next, w.End)
+	}
+	if got := o.When(before, w.End.Sub(w.Start)); got == nil || !got.Equal(w.Start) {
+		t.Errorf("OpenHours.When() = %v, want %v", got, w.Start)
+	}
+	if got, want := o.AddOpen(before, time.Minute), w.Start.Add(time.Minute); !got.Equal(want) {
+		t.Errorf("OpenHours.AddOpen() = %v, want %v", got, want)
+	}
+	if got := o.OpenBetween(before, w.End); got != w.End.Sub(w.Start) {
+		t.Errorf("OpenHours.OpenBetween() = %v, want %v", got, w.End.Sub(w.Start))
+	}
+	if last := w.End.Add(-time.Minute); !o.Match(last) {
+		t.Errorf("OpenHours.Match(%v) = false, want true", last)
+	}
+}
+
+func TestOpenHours_WithDSTPolicy_Parse(t *testing.T) {
+	tests := map[string]func(o *OpenHours) error{
+		"UnmarshalText": func(o *OpenHours) error { return o.UnmarshalText([]byte("su 01:30-03:00")) },
+		"UnmarshalJSON": func(o *OpenHours) error { return o.UnmarshalJSON([]byte(`{"hours":"su 01:30-03:00"}`)) },
+		"Scan":          func(o *OpenHours) error { return o.Scan("su 01:30-03:00") },
+	}
+	for name, parse := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := OpenHours{loc: l}.WithDSTPolicy(DSTSkip)
+			if err := parse(&o); err != nil {
+				t.Fatal(err)
+			}
+			if o.dst != DSTSkip {
+				t.Errorf("OpenHours.%v() policy = %v, want %v", name, o.dst, DSTSkip)
+			}
+			if o.Match(time.Date(2019, 3, 31, 2, 30, 0, 0, l)) {
+				t.Errorf("OpenHours.%v() is open on the spring forward day", name)
+			}
+		})
+	}
+}
+
+func equalIntervals(a, b []Interval) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Start.Equal(b[i].Start) || !a[i].End.Equal(b[i].End) {
+			return false
+		}
+	}
+	return true
+}
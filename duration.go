@@ -0,0 +1,136 @@
+package openhours
+
+import "time"
+
+// AddOpen returns the date when d of open time has passed since t, going over as many windows as needed.
+// It returns the zero time if there is not that much open time in the next few years, see SubOpen for a negative d.
+func (o OpenHours) AddOpen(t time.Time, d time.Duration) time.Time {
+	if d < 0 {
+		return o.SubOpen(t, -d)
+	}
+	if d == 0 {
+		return t
+	}
+	for from, days := t, 0; days <= horizon; days += 7 {
+		to := from.AddDate(0, 0, 7)
+		w := o.windows(from, to)
+		for i := 1; i < len(w); i += 2 {
+			start, end := clip(w[i-1], w[i], from, to)
+			if !start.Before(end) {
+				continue
+			}
+			if open := end.Sub(start); d > open {
+				d -= open
+				continue
+			}
+			return start.Add(d).In(t.Location())
+		}
+		from = to
+	}
+	return time.Time{}
+}
+
+// SubOpen returns the date when d of open time is left before t, going back over as many windows as needed.
+// It returns the zero time if there is not that much open time in the last few years, see AddOpen for a negative d.
+func (o OpenHours) SubOpen(t time.Time, d time.Duration) time.Time {
+	if d < 0 {
+		return o.AddOpen(t, -d)
+	}
+	if d == 0 {
+		return t
+	}
+	for to, days := t, 0; days <= horizon; days += 7 {
+		from := to.AddDate(0, 0, -7)
+		w := o.windows(from, to)
+		for i := len(w) - 1; i > 0; i -= 2 {
+			start, end := clip(w[i-1], w[i], from, to)
+			if !start.Before(end) {
+				continue
+			}
+			if open := end.Sub(start); d > open {
+				d -= open
+				continue
+			}
+			return end.Add(-d).In(t.Location())
+		}
+		to = from
+	}
+	return time.Time{}
+}
+
+// clip returns the part of the window from start to end which is between from and to
+func clip(start, end, from, to time.Time) (time.Time, time.Time) {
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(to) {
+		end = to
+	}
+	return start, end
+}
+
+// OpenBetween returns the open time between from and to.
+// The weeks without a change of time offset are counted at once, so that long spans stay quick.
+func (o OpenHours) OpenBetween(from, to time.Time) time.Duration {
+	if !from.Before(to) {
+		return 0
+	}
+	if o.dated() {
+		return openTime(o.windows(from, to), from, to)
+	}
+	loc := o.location()
+	from, to = from.In(loc), to.In(loc)
+	first := sunday(from)
+	if first.Before(from) {
+		first = first.AddDate(0, 0, 7)
+	}
+	last := sunday(to)
+	if !first.Before(last) {
+		return openTime(o.windows(from, to), from, to)
+	}
+	total := openTime(o.windows(from, first), from, first) + openTime(o.windows(last, to), last, to)
+	week := o.weekOpen()
+	weeks := int(last.Sub(first).Round(24*time.Hour).Hours()) / 24 / 7
+	total += time.Duration(weeks) * week
+	seen := time.Time{}
+	for t := first; ; { // the weeks with a change of offset are counted again with their real windows
+		_, end := t.ZoneBounds()
+		if end.IsZero() || !end.Before(last) {
+			break
+		}
+		if start := sunday(end.In(loc)); !start.Equal(seen) {
+			next := start.AddDate(0, 0, 7)
+			total += openTime(o.windows(start, next), start, next) - week
+			seen = start
+		}
+		t = end
+	}
+	return total
+}
+
+// sunday returns the start of the week of t
+func sunday(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day()-int(t.Weekday()), 0, 0, 0, 0, t.Location())
+}
+
+// weekOpen returns the open time of a week without any change of time offset
+func (o OpenHours) weekOpen() time.Duration {
+	week := inLocation(o.week, time.UTC)
+	w := make([]time.Time, 0, 2*len(week))
+	for _, d := range week { // the windows going past saturday overlap the ones of sunday
+		w = append(w, d.AddDate(0, 0, -7))
+	}
+	w = merge(append(w, week...))
+	return openTime(w, newDate(0, 0, 0, 0, 0, time.UTC), newDate(7, 0, 0, 0, 0, time.UTC))
+}
+
+// openTime returns the open time of the windows w between from and to
+func openTime(w []time.Time, from, to time.Time) time.Duration {
+	total := time.Duration(0)
+	for i := 1; i < len(w); i += 2 {
+		if start, end := clip(w[i-1], w[i], from, to); start.Before(end) {
+			total += end.Sub(start)
+		}
+	}
+	return total
+}
@@ -0,0 +1,109 @@
+package openhours
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOpenHours_AddOpen(t *testing.T) {
+	tests := []struct {
+		name string
+		oh   string
+		t    time.Time
+		d    time.Duration
+		want time.Time
+	}{
+		{"same window", "mo-fr 09:00-17:00", time.Date(2019, 3, 6, 10, 0, 0, 0, l), 6 * time.Hour, time.Date(2019, 3, 6, 16, 0, 0, 0, l)},
+		{"end of window", "mo-fr 09:00-17:00", time.Date(2019, 3, 6, 10, 0, 0, 0, l), 7 * time.Hour, time.Date(2019, 3, 6, 17, 0, 0, 0, l)},
+		{"next day", "mo-fr 09:00-17:00", time.Date(2019, 3, 6, 10, 0, 0, 0, l), 8 * time.Hour, time.Date(2019, 3, 7, 10, 0, 0, 0, l)},
+		{"over the weekend", "mo-fr 09:00-17:00", time.Date(2019, 3, 8, 16, 0, 0, 0, l), 2 * time.Hour, time.Date(2019, 3, 11, 10, 0, 0, 0, l)},
+		{"closed", "mo-fr 09:00-17:00", time.Date(2019, 3, 9, 12, 0, 0, 0, l), time.Hour, time.Date(2019, 3, 11, 10, 0, 0, 0, l)},
+		{"many windows", "mo-fr 09:00-12:00,13:00-17:00", time.Date(2019, 3, 6, 11, 0, 0, 0, l), 12 * time.Hour, time.Date(2019, 3, 7, 17, 0, 0, 0, l)},
+		{"weeks", "mo-fr 09:00-17:00", time.Date(2019, 3, 4, 9, 0, 0, 0, l), 81 * time.Hour, time.Date(2019, 3, 18, 10, 0, 0, 0, l)},
+		{"over midnight", "sa 22:00-02:00", time.Date(2019, 3, 9, 23, 0, 0, 0, l), 2 * time.Hour, time.Date(2019, 3, 10, 1, 0, 0, 0, l)},
+		{"always", "", time.Date(2019, 3, 6, 10, 0, 0, 0, l), 48 * time.Hour, time.Date(2019, 3, 8, 10, 0, 0, 0, l)},
+		{"zero", "mo-fr 09:00-17:00", time.Date(2019, 3, 9, 12, 0, 0, 0, l), 0, time.Date(2019, 3, 9, 12, 0, 0, 0, l)},
+		{"never", "su off", time.Date(2019, 3, 9, 12, 0, 0, 0, l), time.Hour, time.Time{}},
+		{"spring forward", "su 00:00-04:00", time.Date(2019, 3, 31, 0, 0, 0, 0, l), 3 * time.Hour, time.Date(2019, 3, 31, 4, 0, 0, 0, l)},
+		{"spring forward next week", "su 00:00-04:00", time.Date(2019, 3, 31, 0, 0, 0, 0, l), 3*time.Hour + 30*time.Minute, time.Date(2019, 4, 7, 0, 30, 0, 0, l)},
+		{"fall back", "su 00:00-04:00", time.Date(2019, 10, 27, 0, 0, 0, 0, l), 4 * time.Hour, time.Date(2019, 10, 27, 3, 0, 0, 0, l)},
+		{"dated", "Apr-Oct 10:00-12:00", time.Date(2019, 3, 31, 12, 0, 0, 0, l), 3 * time.Hour, time.Date(2019, 4, 2, 11, 0, 0, 0, l)},
+		{"other location", "mo-fr 09:00-17:00", time.Date(2019, 3, 6, 10, 0, 0, 0, time.UTC), 6 * time.Hour, time.Date(2019, 3, 6, 16, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := NewMust(tt.oh, l)
+			if got := o.AddOpen(tt.t, tt.d); !got.Equal(tt.want) {
+				t.Errorf("OpenHours.AddOpen() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenHours_SubOpen(t *testing.T) {
+	tests := []struct {
+		name string
+		oh   string
+		t    time.Time
+		d    time.Duration
+		want time.Time
+	}{
+		{"same window", "mo-fr 09:00-17:00", time.Date(2019, 3, 6, 16, 0, 0, 0, l), 6 * time.Hour, time.Date(2019, 3, 6, 10, 0, 0, 0, l)},
+		{"start of window", "mo-fr 09:00-17:00", time.Date(2019, 3, 6, 16, 0, 0, 0, l), 7 * time.Hour, time.Date(2019, 3, 6, 9, 0, 0, 0, l)},
+		{"previous day", "mo-fr 09:00-17:00", time.Date(2019, 3, 7, 10, 0, 0, 0, l), 2 * time.Hour, time.Date(2019, 3, 6, 16, 0, 0, 0, l)},
+		{"over the weekend", "mo-fr 09:00-17:00", time.Date(2019, 3, 11, 10, 0, 0, 0, l), 2 * time.Hour, time.Date(2019, 3, 8, 16, 0, 0, 0, l)},
+		{"closed", "mo-fr 09:00-17:00", time.Date(2019, 3, 9, 12, 0, 0, 0, l), time.Hour, time.Date(2019, 3, 8, 16, 0, 0, 0, l)},
+		{"back to saturday", "sa 22:00-02:00", time.Date(2019, 3, 10, 1, 0, 0, 0, l), 2 * time.Hour, time.Date(2019, 3, 9, 23, 0, 0, 0, l)},
+		{"back to last week", "mo 10:00-12:00", time.Date(2019, 3, 4, 11, 0, 0, 0, l), 2 * time.Hour, time.Date(2019, 2, 25, 11, 0, 0, 0, l)},
+		{"negative", "mo-fr 09:00-17:00", time.Date(2019, 3, 6, 10, 0, 0, 0, l), -8 * time.Hour, time.Date(2019, 3, 7, 10, 0, 0, 0, l)},
+		{"never", "su off", time.Date(2019, 3, 9, 12, 0, 0, 0, l), time.Hour, time.Time{}},
+		{"spring forward", "su 00:00-04:00", time.Date(2019, 3, 31, 4, 0, 0, 0, l), 3 * time.Hour, time.Date(2019, 3, 31, 0, 0, 0, 0, l)},
+		{"fall back", "su 00:00-04:00", time.Date(2019, 10, 27, 4, 0, 0, 0, l), 5 * time.Hour, time.Date(2019, 10, 27, 0, 0, 0, 0, l)},
+		{"dated", "Apr-Oct 10:00-12:00", time.Date(2019, 11, 1, 12, 0, 0, 0, l), 3 * time.Hour, time.Date(2019, 10, 30, 11, 0, 0, 0, l)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := NewMust(tt.oh, l)
+			if got := o.SubOpen(tt.t, tt.d); !got.Equal(tt.want) {
+				t.Errorf("OpenHours.SubOpen() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenHours_OpenBetween(t *testing.T) {
+	tests := []struct {
+		name     string
+		oh       string
+		from, to time.Time
+		want     time.Duration
+	}{
+		{"week", "mo-fr 09:00-17:00", time.Date(2019, 3, 4, 0, 0, 0, 0, l), time.Date(2019, 3, 11, 0, 0, 0, 0, l), 40 * time.Hour},
+		{"days", "mo-fr 09:00-17:00", time.Date(2019, 3, 6, 10, 0, 0, 0, l), time.Date(2019, 3, 7, 12, 0, 0, 0, l), 10 * time.Hour},
+		{"closed", "mo-fr 09:00-17:00", time.Date(2019, 3, 9, 10, 0, 0, 0, l), time.Date(2019, 3, 10, 12, 0, 0, 0, l), 0},
+		{"months", "mo-fr 09:00-17:00", time.Date(2019, 1, 7, 0, 0, 0, 0, l), time.Date(2019, 4, 1, 0, 0, 0, 0, l), 480 * time.Hour},
+		{"over midnight", "sa 22:00-02:00", time.Date(2019, 3, 10, 1, 0, 0, 0, l), time.Date(2019, 3, 16, 23, 0, 0, 0, l), 2 * time.Hour},
+		{"spring forward", "su 00:00-04:00", time.Date(2019, 3, 1, 0, 0, 0, 0, l), time.Date(2019, 4, 30, 0, 0, 0, 0, l), 35 * time.Hour},
+		{"fall back", "su 00:00-04:00", time.Date(2019, 10, 1, 0, 0, 0, 0, l), time.Date(2019, 11, 30, 0, 0, 0, 0, l), 33 * time.Hour},
+		{"year", "", time.Date(2019, 1, 1, 0, 0, 0, 0, l), time.Date(2020, 1, 1, 0, 0, 0, 0, l), 365 * 24 * time.Hour},
+		{"backwards", "", time.Date(2019, 1, 2, 0, 0, 0, 0, l), time.Date(2019, 1, 1, 0, 0, 0, 0, l), 0},
+		{"dated", "Apr-Oct 10:00-12:00", time.Date(2019, 3, 30, 0, 0, 0, 0, l), time.Date(2019, 4, 3, 0, 0, 0, 0, l), 4 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := NewMust(tt.oh, l)
+			if got := o.OpenBetween(tt.from, tt.to); got != tt.want {
+				t.Errorf("OpenHours.OpenBetween() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	t.Run("windows", func(t *testing.T) {
+		o := NewMust("mo-fr 09:00-17:00; sa 22:00-02:00; su 01:00-03:00", l)
+		for from := time.Date(2019, 1, 1, 0, 30, 0, 0, l); from.Year() == 2019; from = from.Add(61 * time.Hour) {
+			to := from.AddDate(0, 2, 3)
+			if got, want := o.OpenBetween(from, to), openTime(o.windows(from, to), from, to); got != want {
+				t.Fatalf("OpenHours.OpenBetween(%v, %v) = %v, want %v", from, to, got, want)
+			}
+		}
+	})
+}
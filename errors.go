@@ -0,0 +1,85 @@
+package openhours
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// Reason is the kind of mistake found by the parser
+type Reason int
+
+const (
+	ReasonMissingTimes    Reason = iota + 1 // the rule has no time range: "mo"
+	ReasonMissingRangeEnd                   // the time range has no end: "mo 10:00"
+	ReasonBadTimeRange                      // the time range has too many parts: "mo 10:00-12:00-14:00"
+	ReasonBadTime                           // the time is not a valid hour: "mo 10:00-25:99", strict only
+	ReasonUnknownWeekday                    // the day is not a known week day: "mardi 10:00-12:00", strict only
+	ReasonBadDate                           // the month or date selector is invalid: "dec 32 10:00-12:00"
+	ReasonUnexpectedToken                   // something follows the rule: "mo 10:00-12:00 foo", strict only
+)
+
+var reasons = map[Reason]string{
+	ReasonMissingTimes:    "missing time range",
+	ReasonMissingRangeEnd: "missing range end",
+	ReasonBadTimeRange:    "bad time range",
+	ReasonBadTime:         "bad time",
+	ReasonUnknownWeekday:  "unknown weekday",
+	ReasonBadDate:         "bad date",
+	ReasonUnexpectedToken: "unexpected token",
+}
+
+func (r Reason) String() string {
+	if str, exist := reasons[r]; exist {
+		return str
+	}
+	return fmt.Sprintf("Reason(%d)", int(r))
+}
+
+// ParseError is the error returned when a string can not be parsed, it matches ErrInvalidFormat with errors.Is
+type ParseError struct {
+	Rule   int    // index of the rule, rules being separated by ";"
+	Offset int    // byte offset of Token in the parsed string
+	Token  string // offending part of the parsed string
+	Reason Reason
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%v: %v %q at offset %d of rule %d", ErrInvalidFormat, e.Reason, e.Token, e.Offset, e.Rule)
+}
+
+// Unwrap returns ErrInvalidFormat
+func (e *ParseError) Unwrap() error {
+	return ErrInvalidFormat
+}
+
+// parseError returns a ParseError for token found at offset of the string being parsed
+func parseError(reason Reason, token string, offset int) *ParseError {
+	return &ParseError{Offset: offset, Token: token, Reason: reason}
+}
+
+// shift moves the error by n bytes, for errors found in a part of the string
+func (e *ParseError) shift(n int) *ParseError {
+	e.Offset += n
+	return e
+}
+
+// in sets the rule of the error and maps its position in clean back to str, offsets coming from cleanStrOffsets
+func (e *ParseError) in(str string, offsets []int, rule int) *ParseError {
+	e.Rule = rule
+	e.Offset, e.Token = span(str, offsets, e.Offset, len(e.Token))
+	return e
+}
+
+// span returns the offset and the text in str of length bytes at offset of its clean string
+func span(str string, offsets []int, offset, length int) (int, string) {
+	start, end := len(str), len(str)
+	if offset < len(offsets) {
+		start = offsets[offset]
+		end = start
+	}
+	if last := offset + length - 1; length > 0 && last < len(offsets) {
+		_, size := utf8.DecodeRuneInString(str[offsets[last]:])
+		end = offsets[last] + size
+	}
+	return start, str[start:end]
+}
@@ -0,0 +1,92 @@
+package openhours
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNew_ParseError(t *testing.T) {
+	tests := []struct {
+		name string
+		args string
+		want *ParseError
+	}{
+		{"missing range end", "Mo 10:00", &ParseError{Rule: 0, Offset: 3, Token: "10:00", Reason: ReasonMissingRangeEnd}},
+		{"second window", "Mo 10:00-12:00,13:00", &ParseError{Rule: 0, Offset: 15, Token: "13:00", Reason: ReasonMissingRangeEnd}},
+		{"second window with spaces", "Mo 10:00-12:00 , 13:00", &ParseError{Rule: 0, Offset: 17, Token: "13:00", Reason: ReasonMissingRangeEnd}},
+		{"second rule", "Mo 10:00-12:00;  Tu  10:00-11:00-12:00", &ParseError{Rule: 1, Offset: 21, Token: "10:00-11:00-12:00", Reason: ReasonBadTimeRange}},
+		{"missing times", "Mo 10:00-12:00; Tu", &ParseError{Rule: 1, Offset: 16, Token: "Tu", Reason: ReasonMissingTimes}},
+		{"missing times with date", "Mo 10:00-12:00; Dec 25", &ParseError{Rule: 1, Offset: 16, Token: "Dec 25", Reason: ReasonMissingTimes}},
+		{"empty rule", "Mo 10:00-12:00;;Tu 10:00-12:00", &ParseError{Rule: 1, Offset: 15, Token: "", Reason: ReasonMissingTimes}},
+		{"bad date", "Jun-Aug,Dec 32 Mo 10:00-12:00", &ParseError{Rule: 0, Offset: 8, Token: "Dec 32", Reason: ReasonBadDate}},
+		{"bad date range", "Jun-Aug-Sep Mo 10:00-12:00", &ParseError{Rule: 0, Offset: 0, Token: "Jun-Aug-Sep", Reason: ReasonBadDate}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.args, nil)
+			if !errors.Is(err, ErrInvalidFormat) {
+				t.Errorf("New() error = %v, want %v", err, ErrInvalidFormat)
+			}
+			var got *ParseError
+			if !errors.As(err, &got) || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("New() error = %#v, want %#v", got, tt.want)
+			}
+			if got != nil && tt.args[got.Offset:got.Offset+len(got.Token)] != got.Token {
+				t.Errorf("ParseError.Token %q is not at offset %d", got.Token, got.Offset)
+			}
+		})
+	}
+}
+
+func TestParseError_Error(t *testing.T) {
+	err := &ParseError{Rule: 1, Offset: 16, Token: "Tu", Reason: ReasonMissingTimes}
+	if got, want := err.Error(), `invalid format: missing time range "Tu" at offset 16 of rule 1`; got != want {
+		t.Errorf("ParseError.Error() = %v, want %v", got, want)
+	}
+	if got, want := Reason(42).String(), "Reason(42)"; got != want {
+		t.Errorf("Reason.String() = %v, want %v", got, want)
+	}
+}
+
+func TestNewStrict(t *testing.T) {
+	tests := []struct {
+		name string
+		args string
+		want *ParseError
+	}{
+		{"valid", "Mo-Fr 08:00-18:00; We 12:00-14:00 off; PH off", nil},
+		{"after midnight", "Fr,Sa 22:00-26:00", nil},
+		{"seconds", "Mo 08:00:30-18:00", nil},
+		{"single digit hour", "Mo 8:00-18:00", nil},
+		{"unknown day", "mo,mardi 10:00-12:00", &ParseError{Offset: 3, Token: "mardi", Reason: ReasonUnknownWeekday}},
+		{"unknown day range", "mo-pl 10:00-12:00", &ParseError{Offset: 0, Token: "mo-pl", Reason: ReasonUnknownWeekday}},
+		{"empty day", "mo,,tu 10:00-12:00", &ParseError{Offset: 3, Token: "", Reason: ReasonUnknownWeekday}},
+		{"bad minutes", "Mo 10:00-25:99", &ParseError{Offset: 9, Token: "25:99", Reason: ReasonBadTime}},
+		{"bad hours", "Mo 10:00-12:00,33:33-34:00", &ParseError{Offset: 15, Token: "33:33", Reason: ReasonBadTime}},
+		{"letters", "Mo 10:00-12:ab", &ParseError{Offset: 9, Token: "12:ab", Reason: ReasonBadTime}},
+		{"24:01", "Mo 10:00-24:01", &ParseError{Offset: 9, Token: "24:01", Reason: ReasonBadTime}},
+		{"full day past midnight", "Mo 10:00-34:00", &ParseError{Offset: 9, Token: "34:00", Reason: ReasonBadTime}},
+		{"opening past midnight", "Mo 25:00-26:00", &ParseError{Offset: 3, Token: "25:00", Reason: ReasonBadTime}},
+		{"trailing token", "Mo 10:00-12:00 foo", &ParseError{Offset: 15, Token: "foo", Reason: ReasonUnexpectedToken}},
+		{"trailing token after off", "Mo 10:00-12:00 off foo", &ParseError{Offset: 19, Token: "foo", Reason: ReasonUnexpectedToken}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewStrict(tt.args, nil)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("NewStrict() error = %v", err)
+				}
+				return
+			}
+			var got *ParseError
+			if !errors.As(err, &got) || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewStrict() error = %#v, want %#v", got, tt.want)
+			}
+			if _, err := New(tt.args, nil); err != nil {
+				t.Errorf("New() error = %v, lenient parsing should accept it", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package openhours
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Date is a day of the calendar
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// DateOf returns the date of the day of t, in its location
+func DateOf(t time.Time) Date {
+	y, m, d := t.Date()
+	return Date{y, m, d}
+}
+
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// Exceptions replace the windows of some dates, given like the times of a rule ("09:00-14:00,16:00-18:00"),
+// "off" or "closed" closing the whole day. A window of the day before going past midnight is kept.
+type Exceptions map[Date]string
+
+// WithExceptions returns the open hours using e before their rules, the times being checked like NewStrict does.
+// The exceptions are written by MarshalJSON but not by OSMString, MarshalText and Value return ErrExceptions.
+func (o OpenHours) WithExceptions(e Exceptions) (OpenHours, error) {
+	if len(e) == 0 {
+		o.ex = nil
+		return o, nil
+	}
+	ex := make(map[Date][]time.Duration, len(e))
+	start := newDate(1, 0, 0, 0, 0, time.UTC)
+	for date, str := range e {
+		str = cleanStr(str)
+		ex[date] = []time.Duration{}
+		if isOff(str) {
+			continue
+		}
+		if str == "" {
+			return o, fmt.Errorf("%v: %w", date, parseError(ReasonMissingTimes, str, 0))
+		}
+		week, err := simplifyWindows([]int{1}, str, time.UTC, true)
+		if err != nil {
+			return o, fmt.Errorf("%v: %w", date, err)
+		}
+		for _, d := range merge(week) {
+			ex[date] = append(ex[date], d.Sub(start))
+		}
+	}
+	o.ex = ex
+	return o, nil
+}
+
+// formatException returns the windows of an exception like they are given, "off" if there is none
+func formatException(ex []time.Duration) string {
+	if len(ex) == 0 {
+		return "off"
+	}
+	windows := make([]string, 0, len(ex)/2)
+	for i := 0; i < len(ex)-1; i += 2 {
+		windows = append(windows, formatDuration(ex[i])+"-"+formatDuration(ex[i+1]))
+	}
+	return strings.Join(windows, ",")
+}
+
+// formatDuration returns the time from the start of the day as "15:04", with the seconds if any, past "24:00" the day after
+func formatDuration(d time.Duration) string {
+	h, m, sec := int(d/time.Hour), int(d/time.Minute)%60, int(d/time.Second)%60
+	str := strconv.Itoa(h)
+	if h < 10 {
+		str = "0" + str
+	}
+	str += ":" + twoDigits(m)
+	if sec != 0 {
+		str += ":" + twoDigits(sec)
+	}
+	return str
+}
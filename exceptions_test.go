@@ -0,0 +1,81 @@
+package openhours
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOpenHours_WithExceptions(t *testing.T) {
+	o, err := NewMust("mo-fr 09:00-18:00", l).WithExceptions(Exceptions{
+		{2019, time.December, 24}: "09:00-14:00",
+		{2019, time.December, 25}: "off",
+		{2019, time.December, 29}: "10:00-12:00, 22:00-02:00",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	matches := []struct {
+		args time.Time
+		want bool
+	}{
+		{time.Date(2019, 12, 23, 15, 0, 0, 0, l), true},
+		{time.Date(2019, 12, 24, 10, 0, 0, 0, l), true},
+		{time.Date(2019, 12, 24, 15, 0, 0, 0, l), false},
+		{time.Date(2019, 12, 25, 10, 0, 0, 0, l), false},
+		{time.Date(2019, 12, 22, 11, 0, 0, 0, l), false},
+		{time.Date(2019, 12, 29, 11, 0, 0, 0, l), true},
+		{time.Date(2019, 12, 30, 1, 0, 0, 0, l), true},
+		{time.Date(2019, 12, 30, 3, 0, 0, 0, l), false},
+	}
+	for _, tt := range matches {
+		if got := o.Match(tt.args); got != tt.want {
+			t.Errorf("OpenHours.Match(%v) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+	nexts := []struct {
+		args  time.Time
+		want  bool
+		want1 time.Time
+	}{
+		{time.Date(2019, 12, 24, 10, 0, 0, 0, l), true, time.Date(2019, 12, 24, 14, 0, 0, 0, l)},
+		{time.Date(2019, 12, 24, 15, 0, 0, 0, l), false, time.Date(2019, 12, 26, 9, 0, 0, 0, l)},
+		{time.Date(2019, 12, 28, 15, 0, 0, 0, l), false, time.Date(2019, 12, 29, 10, 0, 0, 0, l)},
+	}
+	for _, tt := range nexts {
+		got, got1 := o.NextDate(tt.args)
+		if got != tt.want || !got1.Equal(tt.want1) {
+			t.Errorf("OpenHours.NextDate(%v) = %v, %v, want %v, %v", tt.args, got, got1, tt.want, tt.want1)
+		}
+	}
+	if got, want := o.When(time.Date(2019, 12, 24, 13, 0, 0, 0, l), 2*time.Hour), time.Date(2019, 12, 26, 9, 0, 0, 0, l); got == nil || !got.Equal(want) {
+		t.Errorf("OpenHours.When() = %v, want %v", got, want)
+	}
+	gotIntervals := o.Intervals(time.Date(2019, 12, 24, 0, 0, 0, 0, l), time.Date(2019, 12, 30, 0, 0, 0, 0, l))
+	wantIntervals := []Interval{
+		{time.Date(2019, 12, 24, 9, 0, 0, 0, l), time.Date(2019, 12, 24, 14, 0, 0, 0, l)},
+		{time.Date(2019, 12, 26, 9, 0, 0, 0, l), time.Date(2019, 12, 26, 18, 0, 0, 0, l)},
+		{time.Date(2019, 12, 27, 9, 0, 0, 0, l), time.Date(2019, 12, 27, 18, 0, 0, 0, l)},
+		{time.Date(2019, 12, 29, 10, 0, 0, 0, l), time.Date(2019, 12, 29, 12, 0, 0, 0, l)},
+		{time.Date(2019, 12, 29, 22, 0, 0, 0, l), time.Date(2019, 12, 30, 0, 0, 0, 0, l)},
+	}
+	if !reflect.DeepEqual(gotIntervals, wantIntervals) {
+		t.Errorf("OpenHours.Intervals() = %v, want %v", gotIntervals, wantIntervals)
+	}
+	if got := o.OSMString(); got != "Mo-Fr 09:00-18:00" {
+		t.Errorf("OpenHours.OSMString() = %v, want Mo-Fr 09:00-18:00", got)
+	}
+}
+
+func TestOpenHours_WithExceptions_Errors(t *testing.T) {
+	tests := []string{"", "09:00", "09:00-25:99", "sometimes"}
+	for _, str := range tests {
+		t.Run(str, func(t *testing.T) {
+			_, err := NewMust("mo-fr 09:00-18:00", l).WithExceptions(Exceptions{{2019, time.December, 24}: str})
+			if !errors.Is(err, ErrInvalidFormat) {
+				t.Errorf("OpenHours.WithExceptions() error = %v, want %v", err, ErrInvalidFormat)
+			}
+		})
+	}
+}
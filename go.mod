@@ -1,3 +1,3 @@
-module github.com/chneau/openhours
+module github.com/chneau/openhours/v2
 
-go 1.20
+go 1.23
@@ -0,0 +1,121 @@
+package openhours
+
+import "time"
+
+// HolidayCalendar tells which days are holidays, for the "PH" and "SH" rules.
+// IsHoliday is called with a time at noon of the day, in the location of the open hours.
+type HolidayCalendar interface {
+	IsHoliday(t time.Time) bool
+}
+
+// HolidayFunc is an adapter to use a function as a HolidayCalendar
+type HolidayFunc func(t time.Time) bool
+
+// IsHoliday returns f(t)
+func (f HolidayFunc) IsHoliday(t time.Time) bool {
+	return f(t)
+}
+
+func isHoliday(cal HolidayCalendar, t time.Time) bool {
+	return cal != nil && cal.IsHoliday(t)
+}
+
+// WithPublicHolidays returns the open hours using cal for the "PH" rules
+func (o OpenHours) WithPublicHolidays(cal HolidayCalendar) OpenHours {
+	o.ph = cal
+	return o
+}
+
+// WithSchoolHolidays returns the open hours using cal for the "SH" rules
+func (o OpenHours) WithSchoolHolidays(cal HolidayCalendar) OpenHours {
+	o.sh = cal
+	return o
+}
+
+// Holiday gives the date of a holiday in a year
+type Holiday func(year int) time.Time
+
+// Fixed is a holiday on the same date every year
+func Fixed(month time.Month, day int) Holiday {
+	return func(year int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// Easter is a holiday days after easter sunday (or before if days is negative)
+func Easter(days int) Holiday {
+	return func(year int) time.Time {
+		return easter(year).AddDate(0, 0, days)
+	}
+}
+
+// NthWeekday is the nth weekday of the month, counting from the end of the month if n is negative
+func NthWeekday(month time.Month, weekday time.Weekday, n int) Holiday {
+	return func(year int) time.Time {
+		if n < 0 {
+			last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+			back := (int(last.Weekday()-weekday) + 7) % 7
+			return last.AddDate(0, 0, -back+7*(n+1))
+		}
+		first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+		forward := (int(weekday-first.Weekday()) + 7) % 7
+		return first.AddDate(0, 0, forward+7*(n-1))
+	}
+}
+
+// easter returns the date of easter sunday in the gregorian calendar (anonymous algorithm)
+func easter(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// Calendar is a HolidayCalendar made of yearly holidays
+type Calendar []Holiday
+
+// IsHoliday returns true if the day of t is one of the holidays
+func (c Calendar) IsHoliday(t time.Time) bool {
+	year, month, day := t.Date()
+	for _, h := range c {
+		d := h(year)
+		if d.Month() == month && d.Day() == day {
+			return true
+		}
+	}
+	return false
+}
+
+// PublicHolidays are the national public holidays of a few countries, by ISO 3166 code.
+// Substitute days for holidays falling on a weekend are not included.
+var PublicHolidays = map[string]Calendar{
+	"DE": {
+		Fixed(time.January, 1), Easter(-2), Easter(1), Fixed(time.May, 1), Easter(39), Easter(50),
+		Fixed(time.October, 3), Fixed(time.December, 25), Fixed(time.December, 26),
+	},
+	"DK": {
+		Fixed(time.January, 1), Easter(-3), Easter(-2), Easter(0), Easter(1), Easter(39), Easter(49), Easter(50),
+		Fixed(time.December, 25), Fixed(time.December, 26),
+	},
+	"FR": {
+		Fixed(time.January, 1), Easter(1), Fixed(time.May, 1), Fixed(time.May, 8), Easter(39), Easter(50),
+		Fixed(time.July, 14), Fixed(time.August, 15), Fixed(time.November, 1), Fixed(time.November, 11), Fixed(time.December, 25),
+	},
+	"GB": { // England and Wales
+		Fixed(time.January, 1), Easter(-2), Easter(1), NthWeekday(time.May, time.Monday, 1), NthWeekday(time.May, time.Monday, -1),
+		NthWeekday(time.August, time.Monday, -1), Fixed(time.December, 25), Fixed(time.December, 26),
+	},
+	"US": { // federal holidays
+		Fixed(time.January, 1), NthWeekday(time.January, time.Monday, 3), NthWeekday(time.February, time.Monday, 3),
+		NthWeekday(time.May, time.Monday, -1), Fixed(time.June, 19), Fixed(time.July, 4), NthWeekday(time.September, time.Monday, 1),
+		NthWeekday(time.October, time.Monday, 2), Fixed(time.November, 11), NthWeekday(time.November, time.Thursday, 4), Fixed(time.December, 25),
+	},
+}
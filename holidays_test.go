@@ -0,0 +1,115 @@
+package openhours
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_easter(t *testing.T) {
+	tests := []struct {
+		year int
+		want time.Time
+	}{
+		{2019, time.Date(2019, 4, 21, 0, 0, 0, 0, time.UTC)},
+		{2020, time.Date(2020, 4, 12, 0, 0, 0, 0, time.UTC)},
+		{2024, time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)},
+		{2038, time.Date(2038, 4, 25, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want.String(), func(t *testing.T) {
+			if got := easter(tt.year); !got.Equal(tt.want) {
+				t.Errorf("easter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalendar_IsHoliday(t *testing.T) {
+	tests := []struct {
+		name    string
+		country string
+		day     time.Time
+		want    bool
+	}{
+		{"fr new year", "FR", time.Date(2019, 1, 1, 12, 0, 0, 0, l), true},
+		{"fr bastille day", "FR", time.Date(2019, 7, 14, 12, 0, 0, 0, l), true},
+		{"fr easter monday", "FR", time.Date(2019, 4, 22, 12, 0, 0, 0, l), true},
+		{"fr good friday", "FR", time.Date(2019, 4, 19, 12, 0, 0, 0, l), false},
+		{"de good friday", "DE", time.Date(2019, 4, 19, 12, 0, 0, 0, l), true},
+		{"dk whit monday", "DK", time.Date(2019, 6, 10, 12, 0, 0, 0, l), true},
+		{"gb early may", "GB", time.Date(2019, 5, 6, 12, 0, 0, 0, l), true},
+		{"gb spring", "GB", time.Date(2019, 5, 27, 12, 0, 0, 0, l), true},
+		{"gb summer", "GB", time.Date(2019, 8, 26, 12, 0, 0, 0, l), true},
+		{"gb not a monday", "GB", time.Date(2019, 5, 13, 12, 0, 0, 0, l), false},
+		{"us thanksgiving", "US", time.Date(2019, 11, 28, 12, 0, 0, 0, l), true},
+		{"us mlk", "US", time.Date(2020, 1, 20, 12, 0, 0, 0, l), true},
+		{"us memorial day", "US", time.Date(2020, 5, 25, 12, 0, 0, 0, l), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PublicHolidays[tt.country].IsHoliday(tt.day); got != tt.want {
+				t.Errorf("Calendar.IsHoliday() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenHours_Holidays(t *testing.T) {
+	o := NewMust("Mo-Fr 09:00-18:00; Sa 10:00-14:00; PH off", l).WithPublicHolidays(PublicHolidays["GB"])
+	short := NewMust("Mo-Fr 09:00-18:00; PH 10:00-14:00", l).WithPublicHolidays(PublicHolidays["GB"])
+	school := NewMust("Mo-Fr 09:00-18:00; SH 08:00-12:00", l).WithSchoolHolidays(HolidayFunc(func(t time.Time) bool {
+		return t.Month() == time.August
+	}))
+	tests := []struct {
+		name string
+		o    OpenHours
+		now  time.Time
+		want bool
+	}{
+		{"normal monday", o, time.Date(2019, 5, 13, 10, 0, 0, 0, l), true},
+		{"bank holiday", o, time.Date(2019, 5, 6, 10, 0, 0, 0, l), false},
+		{"no calendar", NewMust("Mo-Fr 09:00-18:00; PH off", l), time.Date(2019, 5, 6, 10, 0, 0, 0, l), true},
+		{"short bank holiday morning", short, time.Date(2019, 5, 6, 9, 30, 0, 0, l), false},
+		{"short bank holiday noon", short, time.Date(2019, 5, 6, 12, 0, 0, 0, l), true},
+		{"open on christmas sunday", short, time.Date(2022, 12, 25, 12, 0, 0, 0, l), true},
+		{"school holiday", school, time.Date(2019, 8, 5, 11, 0, 0, 0, l), true},
+		{"school holiday afternoon", school, time.Date(2019, 8, 5, 15, 0, 0, 0, l), false},
+		{"school day afternoon", school, time.Date(2019, 9, 2, 15, 0, 0, 0, l), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.o.Match(tt.now); got != tt.want {
+				t.Errorf("OpenHours.Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenHours_Holidays_NextDate(t *testing.T) {
+	o := NewMust("Mo-Fr 09:00-18:00; PH off", l).WithPublicHolidays(PublicHolidays["GB"])
+	tests := []struct {
+		name  string
+		now   time.Time
+		want  bool
+		want1 time.Time
+	}{
+		{"easter weekend", time.Date(2019, 4, 18, 19, 0, 0, 0, l), false, time.Date(2019, 4, 23, 9, 0, 0, 0, l)},
+		{"before christmas", time.Date(2019, 12, 24, 12, 0, 0, 0, l), true, time.Date(2019, 12, 24, 18, 0, 0, 0, l)},
+		{"christmas", time.Date(2019, 12, 24, 19, 0, 0, 0, l), false, time.Date(2019, 12, 27, 9, 0, 0, 0, l)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1 := o.NextDate(tt.now)
+			if got != tt.want {
+				t.Errorf("OpenHours.NextDate() got = %v, want %v", got, tt.want)
+			}
+			if !got1.Equal(tt.want1) {
+				t.Errorf("OpenHours.NextDate() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+	}
+	when := o.When(time.Date(2019, 4, 18, 19, 0, 0, 0, l), time.Hour)
+	if want := time.Date(2019, 4, 23, 9, 0, 0, 0, l); when == nil || !when.Equal(want) {
+		t.Errorf("OpenHours.When() = %v, want %v", when, want)
+	}
+}
@@ -0,0 +1,133 @@
+package openhours
+
+import "time"
+
+// hoursPerWeek is the number of buckets of an Index
+const hoursPerWeek = 7 * 24
+
+// Index finds which of many open hours are open at some time (eg: the venues open now), looking only at the ones
+// which may be open during the same hour of the week, on the wall clock of their location.
+// An Index is not safe for concurrent use.
+type Index[K comparable] struct {
+	hours map[K]OpenHours
+	zones map[string]*indexZone[K] // by name of location, time.LoadLocation returning a new one on each call
+}
+
+// indexZone holds the keys of the open hours of a location which may be open during each hour of the week
+type indexZone[K comparable] struct {
+	loc     *time.Location
+	buckets [hoursPerWeek]map[K]struct{}
+}
+
+// NewIndex returns an empty index
+func NewIndex[K comparable]() *Index[K] {
+	return &Index[K]{hours: map[K]OpenHours{}, zones: map[string]*indexZone[K]{}}
+}
+
+// Add adds o to the index, replacing the open hours of key if any
+func (x *Index[K]) Add(key K, o OpenHours) {
+	x.Remove(key)
+	x.hours[key] = o
+	zone, exist := x.zones[o.location().String()]
+	if !exist {
+		zone = &indexZone[K]{loc: o.location()}
+		for i := range zone.buckets {
+			zone.buckets[i] = map[K]struct{}{}
+		}
+		x.zones[o.location().String()] = zone
+	}
+	week, margin := o.week, time.Duration(0)
+	if o.daily() { // the windows read day by day may be moved by a change of offset
+		week, margin = o.mayOpen(), time.Hour
+	}
+	offset := weekZone(week)
+	for i := 0; i < len(week)-1; i += 2 {
+		from, to := dateOffset(week[i], offset)-margin, dateOffset(week[i+1], offset)+margin
+		for h := from / time.Hour; h*time.Hour < to; h++ {
+			zone.buckets[(h+hoursPerWeek)%hoursPerWeek][key] = struct{}{}
+		}
+	}
+}
+
+// mayOpen returns the windows of a generic week in which o may be open, those of the rules which are not off and of the exceptions
+func (o OpenHours) mayOpen() []time.Time {
+	week := o.week
+	if o.rules != nil {
+		week = []time.Time{}
+		for _, r := range o.rules {
+			if !r.off {
+				week = append(week, r.week...)
+			}
+		}
+	}
+	for date, ex := range o.ex {
+		weekday := int(time.Date(date.Year, date.Month, date.Day, 0, 0, 0, 0, time.UTC).Weekday())
+		midnight := newDate(weekday, 0, 0, 0, 0, o.location())
+		for _, d := range ex {
+			week = append(week[:len(week):len(week)], midnight.Add(d))
+		}
+	}
+	return week
+}
+
+// Remove removes the open hours of key from the index
+func (x *Index[K]) Remove(key K) {
+	o, exist := x.hours[key]
+	if !exist {
+		return
+	}
+	delete(x.hours, key)
+	for _, b := range x.zones[o.location().String()].buckets {
+		delete(b, key)
+	}
+}
+
+// Len returns the number of open hours in the index
+func (x *Index[K]) Len() int {
+	return len(x.hours)
+}
+
+// OpenAt returns the keys of the open hours open at t, in no particular order
+func (x *Index[K]) OpenAt(t time.Time) []K {
+	return x.find(t, t, 0, func(o OpenHours) bool { return o.Match(t) })
+}
+
+// OpenDuring returns the keys of the open hours open at some time between from and to, in no particular order
+func (x *Index[K]) OpenDuring(from, to time.Time) []K {
+	return x.find(from, to, 1, func(o OpenHours) bool { return o.OpenBetween(from, to) > 0 })
+}
+
+// OpenFor returns the keys of the open hours open all the time from t to t+d, in no particular order
+func (x *Index[K]) OpenFor(t time.Time, d time.Duration) []K {
+	if d <= 0 {
+		return x.OpenAt(t)
+	}
+	return x.find(t, t.Add(d), 1, func(o OpenHours) bool { return o.OpenBetween(t, t.Add(d)) == d })
+}
+
+// find returns the keys of the open hours which may be open between from and to and for which match is true,
+// from being read on the wall clock of each location. The margin buckets around the hours of from and to are looked at too,
+// for the changes of offset.
+func (x *Index[K]) find(from, to time.Time, margin int, match func(o OpenHours) bool) []K {
+	keys := []K{}
+	seen := map[K]struct{}{}
+	n := int(to.Sub(from)/time.Hour) + 1 + 2*margin
+	if n >= hoursPerWeek {
+		n = hoursPerWeek
+	}
+	for _, zone := range x.zones {
+		first := int(weekOffset(from.In(zone.loc))/time.Hour) - margin
+		for i := 0; i < n; i++ {
+			for key := range zone.buckets[(first+i+hoursPerWeek)%hoursPerWeek] {
+				if _, exist := seen[key]; exist {
+					continue
+				}
+				seen[key] = struct{}{}
+				if match(x.hours[key]) {
+					keys = append(keys, key)
+				}
+			}
+		}
+	}
+	return keys
+}
@@ -0,0 +1,123 @@
+package openhours
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestIndex(t *testing.T) {
+	x := NewIndex[string]()
+	x.Add("office", NewMust("mo-fr 09:00-17:00", l))
+	x.Add("bar", NewMust("mo-sa 18:00-02:00", l))
+	x.Add("shop", NewMust("mo-sa 10:00-19:00", l))
+	x.Add("market", NewMust("jun-aug sa 08:00-13:00", l))
+	x.Add("always", NewMust("", l))
+	friday := time.Date(2019, 7, 5, 0, 0, 0, 0, l)
+	tests := []struct {
+		name string
+		got  func() []string
+		want []string
+	}{
+		{"open at", func() []string { return x.OpenAt(friday.Add(12 * time.Hour)) }, []string{"always", "office", "shop"}},
+		{"open at night", func() []string { return x.OpenAt(friday.Add(25 * time.Hour)) }, []string{"always", "bar"}},
+		{"open at dated", func() []string { return x.OpenAt(friday.Add(33 * time.Hour)) }, []string{"always", "market"}},
+		{"open during", func() []string { return x.OpenDuring(friday.Add(16*time.Hour), friday.Add(18*time.Hour+time.Minute)) }, []string{"always", "bar", "office", "shop"}},
+		{"open for", func() []string { return x.OpenFor(friday.Add(16*time.Hour), 2*time.Hour) }, []string{"always", "shop"}},
+		{"open for a week", func() []string { return x.OpenFor(friday, 8*24*time.Hour) }, []string{"always"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.got()
+			slices.Sort(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+	x.Remove("always")
+	x.Add("office", NewMust("mo-fr 12:00-13:00", l))
+	if got, want := x.OpenAt(friday.Add(10*time.Hour)), []string{"shop"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Index.OpenAt() = %v, want %v", got, want)
+	}
+	if got := x.Len(); got != 4 {
+		t.Errorf("Index.Len() = %v, want 4", got)
+	}
+}
+
+func TestIndex_Zones(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	x := NewIndex[string]()
+	x.Add("tokyo", NewMust("mo 09:00-10:00", tokyo))
+	x.Add("london", NewMust("mo 09:00-10:00", l))
+	x.Add("christmas", NewMust("Dec 25 10:00-12:00", l))
+	x.Add("holiday", NewMust("mo-fr 09:00-17:00; PH off", l).WithPublicHolidays(HolidayFunc(func(t time.Time) bool { return t.Day() == 25 })))
+	x.Add("boxing day", must(NewMust("su off", l).WithExceptions(Exceptions{{2019, time.December, 26}: "20:00-22:00"})))
+	x.Add("shift", NewMust("su 00:30-01:30", l).WithDSTPolicy(DSTElapsed))
+	tests := []struct {
+		at   time.Time
+		want []string
+	}{
+		{time.Date(2019, 12, 23, 0, 30, 0, 0, time.UTC), []string{"tokyo"}},
+		{time.Date(2019, 12, 23, 9, 30, 0, 0, l), []string{"holiday", "london"}},
+		{time.Date(2019, 12, 25, 11, 0, 0, 0, l), []string{"christmas"}},
+		{time.Date(2019, 12, 26, 11, 0, 0, 0, l), []string{"holiday"}},
+		{time.Date(2019, 12, 26, 21, 0, 0, 0, time.UTC), []string{"boxing day"}},
+		{time.Date(2019, 3, 31, 2, 15, 0, 0, l), []string{"shift"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.at.String(), func(t *testing.T) {
+			got := x.OpenAt(tt.at)
+			slices.Sort(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Index.OpenAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if _, exist := x.zones[l.String()].buckets[0]["christmas"]; exist {
+		t.Errorf("christmas should not be looked at on sunday")
+	}
+}
+
+func TestIndex_SameZone(t *testing.T) {
+	x := NewIndex[int]()
+	for i := range 10 {
+		o := OpenHours{}
+		if err := json.Unmarshal([]byte(fmt.Sprintf(`{"hours":"mo %02d:00-%02d:00","location":"America/New_York"}`, i, i+1)), &o); err != nil {
+			t.Fatal(err)
+		}
+		x.Add(i, o)
+	}
+	if len(x.zones) != 1 {
+		t.Errorf("len(Index.zones) = %v, want 1", len(x.zones))
+	}
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := x.OpenAt(time.Date(2019, 3, 4, 3, 30, 0, 0, ny)); !reflect.DeepEqual(got, []int{3}) {
+		t.Errorf("Index.OpenAt() = %v, want [3]", got)
+	}
+	x.Remove(3)
+	if got := x.OpenAt(time.Date(2019, 3, 4, 3, 30, 0, 0, ny)); len(got) != 0 {
+		t.Errorf("Index.OpenAt() = %v, want none", got)
+	}
+}
+
+func BenchmarkIndex_OpenAt(b *testing.B) {
+	x := NewIndex[int]()
+	for i := 0; i < 5000; i++ {
+		x.Add(i, NewMust(fmt.Sprintf("mo-fr %02d:00-%02d:00", i%24, (i+8)%24), l))
+	}
+	t := time.Date(2019, 3, 6, 10, 15, 0, 0, l)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		x.OpenAt(t)
+	}
+}
@@ -0,0 +1,53 @@
+package openhours
+
+import (
+	"iter"
+	"time"
+)
+
+// Interval is an open window on real dates, from Start to End excluded
+type Interval struct {
+	Start, End time.Time
+}
+
+// Intervals returns the open windows between from and to, the first and last ones being cut at from and to
+func (o OpenHours) Intervals(from, to time.Time) []Interval {
+	intervals := []Interval{}
+	for i := range o.All(from, to) {
+		intervals = append(intervals, i)
+	}
+	return intervals
+}
+
+// All returns the open windows between from and to like Intervals, looking at the open hours a week at a time
+func (o OpenHours) All(from, to time.Time) iter.Seq[Interval] {
+	return func(yield func(Interval) bool) {
+		loc := from.Location()
+		current, pending := Interval{}, false
+		for start := from; start.Before(to); {
+			end := start.AddDate(0, 0, 7)
+			if end.After(to) {
+				end = to
+			}
+			w := o.windows(start, end)
+			for i := 1; i < len(w); i += 2 {
+				s, e := clip(w[i-1], w[i], start, end)
+				if !s.Before(e) {
+					continue
+				}
+				if pending && current.End.Equal(s) { // the window goes on from the previous week
+					current.End = e.In(loc)
+					continue
+				}
+				if pending && !yield(current) {
+					return
+				}
+				current, pending = Interval{s.In(loc), e.In(loc)}, true
+			}
+			start = end
+		}
+		if pending {
+			yield(current)
+		}
+	}
+}
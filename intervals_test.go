@@ -0,0 +1,71 @@
+package openhours
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOpenHours_Intervals(t *testing.T) {
+	tests := []struct {
+		name     string
+		oh       string
+		from, to time.Time
+		want     []Interval
+	}{
+		{"cut", "mo-fr 09:00-17:00", time.Date(2019, 3, 6, 10, 0, 0, 0, l), time.Date(2019, 3, 8, 12, 0, 0, 0, l), []Interval{
+			{time.Date(2019, 3, 6, 10, 0, 0, 0, l), time.Date(2019, 3, 6, 17, 0, 0, 0, l)},
+			{time.Date(2019, 3, 7, 9, 0, 0, 0, l), time.Date(2019, 3, 7, 17, 0, 0, 0, l)},
+			{time.Date(2019, 3, 8, 9, 0, 0, 0, l), time.Date(2019, 3, 8, 12, 0, 0, 0, l)},
+		}},
+		{"closed", "mo-fr 09:00-17:00", time.Date(2019, 3, 9, 0, 0, 0, 0, l), time.Date(2019, 3, 10, 0, 0, 0, 0, l), []Interval{}},
+		{"backwards", "", time.Date(2019, 3, 10, 0, 0, 0, 0, l), time.Date(2019, 3, 9, 0, 0, 0, 0, l), []Interval{}},
+		{"over midnight", "mo 22:00-02:00", time.Date(2019, 3, 4, 0, 0, 0, 0, l), time.Date(2019, 3, 6, 0, 0, 0, 0, l), []Interval{
+			{time.Date(2019, 3, 4, 22, 0, 0, 0, l), time.Date(2019, 3, 5, 2, 0, 0, 0, l)},
+		}},
+		{"joined at midnight", "mo 10:00-24:00; tu 00:00-02:00", time.Date(2019, 3, 4, 0, 0, 0, 0, l), time.Date(2019, 3, 6, 0, 0, 0, 0, l), []Interval{
+			{time.Date(2019, 3, 4, 10, 0, 0, 0, l), time.Date(2019, 3, 5, 2, 0, 0, 0, l)},
+		}},
+		{"joined over weeks", "", time.Date(2019, 3, 1, 12, 0, 0, 0, l), time.Date(2019, 3, 20, 12, 0, 0, 0, l), []Interval{
+			{time.Date(2019, 3, 1, 12, 0, 0, 0, l), time.Date(2019, 3, 20, 12, 0, 0, 0, l)},
+		}},
+		{"spring forward", "su 00:00-04:00", time.Date(2019, 3, 30, 0, 0, 0, 0, l), time.Date(2019, 4, 1, 0, 0, 0, 0, l), []Interval{
+			{time.Date(2019, 3, 31, 0, 0, 0, 0, l), time.Date(2019, 3, 31, 4, 0, 0, 0, l)},
+		}},
+		{"in the gap", "su 01:30-03:00", time.Date(2019, 3, 30, 0, 0, 0, 0, l), time.Date(2019, 4, 1, 0, 0, 0, 0, l), []Interval{
+			{time.Date(2019, 3, 31, 2, 30, 0, 0, l), time.Date(2019, 3, 31, 3, 0, 0, 0, l)},
+		}},
+		{"fall back", "su 00:00-04:00", time.Date(2019, 10, 26, 0, 0, 0, 0, l), time.Date(2019, 10, 28, 0, 0, 0, 0, l), []Interval{
+			{time.Date(2019, 10, 27, 0, 0, 0, 0, l), time.Date(2019, 10, 27, 4, 0, 0, 0, l)},
+		}},
+		{"dated", "Apr-Oct 10:00-12:00", time.Date(2019, 3, 30, 0, 0, 0, 0, l), time.Date(2019, 4, 3, 0, 0, 0, 0, l), []Interval{
+			{time.Date(2019, 4, 1, 10, 0, 0, 0, l), time.Date(2019, 4, 1, 12, 0, 0, 0, l)},
+			{time.Date(2019, 4, 2, 10, 0, 0, 0, l), time.Date(2019, 4, 2, 12, 0, 0, 0, l)},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewMust(tt.oh, l).Intervals(tt.from, tt.to); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("OpenHours.Intervals() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenHours_All(t *testing.T) {
+	o := NewMust("mo-fr 09:00-17:00", l)
+	got := []Interval{}
+	for i := range o.All(time.Date(2019, 3, 6, 0, 0, 0, 0, l), time.Date(2119, 3, 6, 0, 0, 0, 0, l)) {
+		if len(got) == 2 {
+			break
+		}
+		got = append(got, i)
+	}
+	want := []Interval{
+		{time.Date(2019, 3, 6, 9, 0, 0, 0, l), time.Date(2019, 3, 6, 17, 0, 0, 0, l)},
+		{time.Date(2019, 3, 7, 9, 0, 0, 0, l), time.Date(2019, 3, 7, 17, 0, 0, 0, l)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("OpenHours.All() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,158 @@
+package openhours
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Window is an opening window of a week day, as written in the JSON form of the open hours
+type Window struct {
+	Day   string `json:"day"`   // "Mo" to "Su"
+	Open  string `json:"open"`  // "09:00"
+	Close string `json:"close"` // "17:00", before Open when closing the next day
+}
+
+// jsonOpenHours is the JSON form of the open hours
+type jsonOpenHours struct {
+	Hours      string            `json:"hours,omitempty"`
+	Windows    []Window          `json:"windows,omitempty"`
+	Location   string            `json:"location,omitempty"`
+	Exceptions map[string]string `json:"exceptions,omitempty"` // "2019-12-24": "09:00-14:00"
+}
+
+// Windows returns the opening windows of the week from monday, without the exceptions,
+// or nil if the open hours have rules depending on the date
+func (o OpenHours) Windows() []Window {
+	if o.rules != nil {
+		return nil
+	}
+	days := o.dayPairs()
+	windows := []Window{}
+	for _, day := range []int{1, 2, 3, 4, 5, 6, 0} {
+		for _, p := range days[day] {
+			windows = append(windows, newWindow(day, p))
+		}
+	}
+	return windows
+}
+
+// newWindow returns the window of the open and close dates p starting on day
+func newWindow(day int, p [2]time.Time) Window {
+	times := strings.SplitN(formatWindow(p[0], p[1]), "-", 2)
+	return Window{Day: osmDays[day], Open: times[0], Close: times[1]}
+}
+
+// windowsString returns the opening hours string of windows
+func windowsString(windows []Window) string {
+	if len(windows) == 0 {
+		return "off"
+	}
+	rules := []string{}
+	for _, w := range windows {
+		rules = append(rules, w.Day+" "+w.Open+"-"+w.Close)
+	}
+	return strings.Join(rules, "; ")
+}
+
+// MarshalText returns the opening hours string of o, without its location.
+// It returns ErrExceptions if o has exceptions, see MarshalJSON.
+func (o OpenHours) MarshalText() ([]byte, error) {
+	if o.ex != nil {
+		return nil, ErrExceptions
+	}
+	return []byte(o.OSMString()), nil
+}
+
+// UnmarshalText parses text with New, in the location of o
+func (o *OpenHours) UnmarshalText(text []byte) error {
+	return o.parse(string(text), o.loc)
+}
+
+// MarshalJSON returns the opening hours string, the windows, the name of the location and the exceptions of o:
+// {"hours":"Mo 09:00-17:00","windows":[{"day":"Mo","open":"09:00","close":"17:00"}],"location":"Europe/London","exceptions":{"2019-12-24":"off"}}
+func (o OpenHours) MarshalJSON() ([]byte, error) {
+	j := jsonOpenHours{Hours: o.OSMString(), Windows: o.Windows(), Location: o.location().String()}
+	if o.ex != nil {
+		j.Exceptions = make(map[string]string, len(o.ex))
+		for date, ex := range o.ex {
+			j.Exceptions[date.String()] = formatException(ex)
+		}
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON reads an object written by MarshalJSON, a list of windows or an opening hours string.
+// The hours of the object are used before its windows, the location of o is kept if none is given, null does nothing.
+// The exceptions are read like WithExceptions does.
+func (o *OpenHours) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		return nil
+	case bytes.HasPrefix(data, []byte(`"`)):
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+		return o.parse(str, o.loc)
+	case bytes.HasPrefix(data, []byte("[")):
+		windows := []Window{}
+		if err := json.Unmarshal(data, &windows); err != nil {
+			return err
+		}
+		return o.parse(windowsString(windows), o.loc)
+	}
+	j := jsonOpenHours{}
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	loc := o.loc
+	if j.Location != "" {
+		var err error
+		if loc, err = time.LoadLocation(j.Location); err != nil {
+			return err
+		}
+	}
+	n := OpenHours{ph: o.ph, sh: o.sh, dst: o.dst}
+	switch {
+	case j.Hours != "":
+		if err := n.parse(j.Hours, loc); err != nil {
+			return err
+		}
+	case j.Windows != nil:
+		if err := n.parse(windowsString(j.Windows), loc); err != nil {
+			return err
+		}
+	default:
+		return ErrInvalidFormat
+	}
+	if j.Exceptions != nil {
+		e := Exceptions{}
+		for str, ex := range j.Exceptions {
+			t, err := time.Parse("2006-01-02", str)
+			if err != nil {
+				return err
+			}
+			e[DateOf(t)] = ex
+		}
+		var err error
+		if n, err = n.WithExceptions(e); err != nil {
+			return err
+		}
+	}
+	*o = n
+	return nil
+}
+
+// parse sets o to New(str, loc), keeping the holiday calendars and the DST policy of o
+func (o *OpenHours) parse(str string, loc *time.Location) error {
+	n, err := New(str, loc)
+	if err != nil {
+		return err
+	}
+	n.ph, n.sh, n.dst = o.ph, o.sh, o.dst
+	*o = n
+	return nil
+}
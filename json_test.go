@@ -0,0 +1,122 @@
+package openhours
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOpenHours_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		args string
+		want string
+	}{
+		{"mo-fr 09:00-17:00", `{"hours":"Mo-Fr 09:00-17:00","windows":[{"day":"Mo","open":"09:00","close":"17:00"},{"day":"Tu","open":"09:00","close":"17:00"},{"day":"We","open":"09:00","close":"17:00"},{"day":"Th","open":"09:00","close":"17:00"},{"day":"Fr","open":"09:00","close":"17:00"}],"location":"Europe/London"}`},
+		{"sa 22:00-02:00; su 10:00-12:00,14:00-24:00", `{"hours":"Sa 22:00-02:00; Su 10:00-12:00,14:00-24:00","windows":[{"day":"Sa","open":"22:00","close":"02:00"},{"day":"Su","open":"10:00","close":"12:00"},{"day":"Su","open":"14:00","close":"24:00"}],"location":"Europe/London"}`},
+		{"su off", `{"hours":"off","location":"Europe/London"}`},
+		{"Apr-Oct 10:00-22:00", `{"hours":"Apr-Oct 10:00-22:00","location":"Europe/London"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.args, func(t *testing.T) {
+			o := NewMust(tt.args, l)
+			got, err := json.Marshal(o)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("json.Marshal() = %s, want %s", got, tt.want)
+			}
+			back := OpenHours{}
+			if err := json.Unmarshal(got, &back); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if back.OSMString() != o.OSMString() || back.location().String() != l.String() {
+				t.Errorf("json.Unmarshal() = %v in %v, want %v in %v", back.OSMString(), back.location(), o.OSMString(), l)
+			}
+		})
+	}
+}
+
+func TestOpenHours_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    string
+		loc     *time.Location
+		want    string
+		wantLoc string
+		wantErr error
+	}{
+		{"string", `"mo-fr 09:00-17:00"`, nil, "Mo-Fr 09:00-17:00", "UTC", nil},
+		{"string keeps location", `"mo-fr 09:00-17:00"`, l, "Mo-Fr 09:00-17:00", "Europe/London", nil},
+		{"windows", `[{"day":"Mo","open":"09:00","close":"12:00"},{"day":"Mo","open":"14:00","close":"18:00"},{"day":"Tu","open":"22:00","close":"02:00"}]`, nil, "Mo 09:00-12:00,14:00-18:00; Tu 22:00-02:00", "UTC", nil},
+		{"no windows", `[]`, nil, "off", "UTC", nil},
+		{"object", `{"hours":"sa 10:00-14:00","location":"America/New_York"}`, l, "Sa 10:00-14:00", "America/New_York", nil},
+		{"object windows", `{"windows":[{"day":"Su","open":"10:00","close":"12:00"}]}`, l, "Su 10:00-12:00", "Europe/London", nil},
+		{"object empty", `{"location":"UTC"}`, nil, "", "", ErrInvalidFormat},
+		{"invalid hours", `"mo 10:00"`, nil, "", "", ErrInvalidFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := OpenHours{loc: tt.loc}
+			err := json.Unmarshal([]byte(tt.args), &o)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("json.Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := o.OSMString(); got != tt.want {
+				t.Errorf("json.Unmarshal() = %v, want %v", got, tt.want)
+			}
+			if got := o.location().String(); got != tt.wantLoc {
+				t.Errorf("json.Unmarshal() location = %v, want %v", got, tt.wantLoc)
+			}
+		})
+	}
+	t.Run("unknown location", func(t *testing.T) {
+		o := OpenHours{}
+		if err := json.Unmarshal([]byte(`{"hours":"mo 10:00-12:00","location":"Nowhere/Land"}`), &o); err == nil {
+			t.Errorf("json.Unmarshal() error = nil, want an error")
+		}
+	})
+}
+
+func TestOpenHours_MarshalText(t *testing.T) {
+	o := NewMust("mo-fr 09:00-17:00; sa 10:00-14:00", l)
+	text, err := o.MarshalText()
+	if err != nil || string(text) != "Mo-Fr 09:00-17:00; Sa 10:00-14:00" {
+		t.Errorf("OpenHours.MarshalText() = %s, %v", text, err)
+	}
+	back := OpenHours{loc: l}
+	if err := back.UnmarshalText(text); err != nil || !reflect.DeepEqual(back, o) {
+		t.Errorf("OpenHours.UnmarshalText() = %v, %v, want %v", back, err, o)
+	}
+}
+
+func TestOpenHours_MarshalJSON_Exceptions(t *testing.T) {
+	o := must(NewMust("mo-fr 09:00-17:00", l).WithExceptions(Exceptions{{2019, time.December, 24}: "09:00-14:00", {2019, time.December, 25}: "off"}))
+	got, err := json.Marshal(o)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	want := `"exceptions":{"2019-12-24":"09:00-14:00","2019-12-25":"off"}}`
+	if !strings.HasSuffix(string(got), want) {
+		t.Errorf("json.Marshal() = %s, want the suffix %s", got, want)
+	}
+	back := OpenHours{}
+	if err := json.Unmarshal(got, &back); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(back, o) {
+		t.Errorf("json.Unmarshal() = %v, want %v", back, o)
+	}
+	if err := json.Unmarshal([]byte(`{"hours":"mo 10:00-12:00","exceptions":{"24/12/2019":"off"}}`), &back); err == nil {
+		t.Errorf("json.Unmarshal() error = nil, want an error")
+	}
+	if text, err := o.MarshalText(); err != ErrExceptions {
+		t.Errorf("OpenHours.MarshalText() = %s, %v, want %v", text, err, ErrExceptions)
+	}
+}
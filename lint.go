@@ -0,0 +1,134 @@
+package openhours
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WarningKind is the kind of suspicious part found by Lint
+type WarningKind int
+
+const (
+	WarningOverlap        WarningKind = iota + 1 // the window overlaps another one of the same day, they are merged: "mo 10:00-12:00,11:00-13:00"
+	WarningEmptyWindow                           // the window is empty or ends before it starts, it is dropped: "mo 10:00-10:00"
+	WarningStartAt24                             // the window starts at the end of the day: "mo 24:00-02:00"
+	WarningDuplicateDay                          // the day is already opened by a previous rule, both are kept: "mo 10:00-12:00; mo-fr 14:00-18:00"
+	WarningTimeNormalized                        // the time is out of range and read as another one: "mo 33:00-35:00" as 09:00-11:00, "25:99" as 00:00
+)
+
+var warningKinds = map[WarningKind]string{
+	WarningOverlap:        "overlapping window",
+	WarningEmptyWindow:    "empty window",
+	WarningStartAt24:      "window starting at 24:00",
+	WarningDuplicateDay:   "duplicate day",
+	WarningTimeNormalized: "normalized time",
+}
+
+func (k WarningKind) String() string {
+	if str, exist := warningKinds[k]; exist {
+		return str
+	}
+	return fmt.Sprintf("WarningKind(%d)", int(k))
+}
+
+// Warning is a suspicious part of a valid opening hours string
+type Warning struct {
+	Rule   int    // index of the rule, rules being separated by ";"
+	Offset int    // byte offset of Token in the string
+	Token  string // suspicious part of the string
+	Kind   WarningKind
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%v %q at offset %d of rule %d", w.Kind, w.Token, w.Offset, w.Rule)
+}
+
+// Lint returns the parts of str which are valid but probably not what was meant.
+// It returns nil if New fails on str.
+func Lint(str string) []Warning {
+	if _, err := New(str, nil); err != nil {
+		return nil
+	}
+	if len(str) > 0 && str[len(str)-1] == ';' {
+		str = str[:len(str)-1]
+	}
+	clean, offsets := cleanStrOffsets(str)
+	warnings := []Warning{}
+	warn := func(kind WarningKind, rule int, p part) {
+		offset, token := span(str, offsets, p.offset, len(p.str))
+		warnings = append(warnings, Warning{Rule: rule, Offset: offset, Token: token, Kind: kind})
+	}
+	type window struct {
+		from, to time.Time
+		dates    string
+		rule     int
+		part     part
+	}
+	windows := []window{}
+	opened := map[string]map[int]bool{} // days opened by the previous rules, by date selector
+	offset := 0
+	for n, str := range strings.Split(clean, ";") {
+		s := splitRule(str)
+		base := offset
+		offset += len(str) + 1
+		days := []int{0, 1, 2, 3, 4, 5, 6}
+		if s.days.str != "" {
+			days = simplifyDays(s.days.str)
+		}
+		if opened[s.dates.str] == nil {
+			opened[s.dates.str] = map[int]bool{}
+		}
+		for _, day := range days {
+			if !s.off && opened[s.dates.str][day] {
+				p := s.days
+				if p.str == "" {
+					p = s.times
+				}
+				warn(WarningDuplicateDay, n, part{p.str, base + p.offset})
+				break
+			}
+		}
+		for _, day := range days {
+			opened[s.dates.str][day] = !s.off
+		}
+		if s.times.str == "" {
+			continue
+		}
+		itemOffset := base + s.times.offset
+		for _, item := range strings.Split(s.times.str, ",") {
+			times := strings.Split(item, "-")
+			hourFrom, minFrom, secFrom := simplifyTime(times[0])
+			hourTo, minTo, secTo := simplifyTime(times[1])
+			from, to := hourFrom*3600+minFrom*60+secFrom, hourTo*3600+minTo*60+secTo
+			if _, ok := strictTime(times[0], 24); !ok {
+				warn(WarningTimeNormalized, n, part{times[0], itemOffset})
+			}
+			if secs, ok := strictTime(times[1], 48); !ok || secs > 24*3600 && secs-24*3600 >= from {
+				warn(WarningTimeNormalized, n, part{times[1], itemOffset + len(times[0]) + 1})
+			}
+			if hourFrom == 24 {
+				warn(WarningStartAt24, n, part{times[0], itemOffset})
+			}
+			if hourFrom <= hourTo && to <= from {
+				warn(WarningEmptyWindow, n, part{item, itemOffset})
+			} else if !s.off {
+				week, _ := simplifyWindows(days, item, time.UTC, false)
+				for i := 0; i < len(week)-1; i += 2 {
+					windows = append(windows, window{week[i], week[i+1], s.dates.str, n, part{item, itemOffset}})
+				}
+			}
+			itemOffset += len(item) + 1
+		}
+	}
+	overlapping := map[part]bool{}
+	for j, b := range windows {
+		for _, a := range windows[:j] {
+			if a.dates == b.dates && a.from.Before(b.to) && b.from.Before(a.to) && !overlapping[b.part] {
+				overlapping[b.part] = true
+				warn(WarningOverlap, b.rule, b.part)
+			}
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,44 @@
+package openhours
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name string
+		args string
+		want []Warning
+	}{
+		{"clean", "Mo-Fr 08:00-12:00,13:00-18:00; Sa 10:00-14:00; Su off", []Warning{}},
+		{"touching windows", "su-sa 00:00-12:00,12:00-24:00", []Warning{}},
+		{"after midnight", "Fr,Sa 22:00-26:00", []Warning{}},
+		{"invalid", "Mo 10:00", nil},
+		{"overlap", "Mo 10:00-12:00, 11:00-13:00", []Warning{{Rule: 0, Offset: 16, Token: "11:00-13:00", Kind: WarningOverlap}}},
+		{"overlap across rules", "Mo-Fr 10:00-18:00; Dec 25 off; We 12:00-19:00", []Warning{
+			{Rule: 2, Offset: 31, Token: "We", Kind: WarningDuplicateDay},
+			{Rule: 2, Offset: 34, Token: "12:00-19:00", Kind: WarningOverlap},
+		}},
+		{"other dates", "Apr-Oct Mo 10:00-22:00; Nov-Mar Mo 12:00-20:00", []Warning{}},
+		{"empty window", "Mo 10:00-10:00", []Warning{{Rule: 0, Offset: 3, Token: "10:00-10:00", Kind: WarningEmptyWindow}}},
+		{"backward window", "Mo 10:30-10:00", []Warning{{Rule: 0, Offset: 3, Token: "10:30-10:00", Kind: WarningEmptyWindow}}},
+		{"start at 24", "Mo 24:00-26:00", []Warning{{Rule: 0, Offset: 3, Token: "24:00", Kind: WarningStartAt24}}},
+		{"duplicate day", "Mo 10:00-12:00; mo-FR 14:00-18:00", []Warning{{Rule: 1, Offset: 16, Token: "mo-FR", Kind: WarningDuplicateDay}}},
+		{"duplicate without days", "Mo 10:00-12:00; 14:00-18:00", []Warning{{Rule: 1, Offset: 16, Token: "14:00-18:00", Kind: WarningDuplicateDay}}},
+		{"reopen after off", "Mo-Fr 10:00-12:00; Mo off; Mo 14:00-18:00", []Warning{}},
+		{"normalized hour", "Mo 33:00-35:00", []Warning{
+			{Rule: 0, Offset: 3, Token: "33:00", Kind: WarningTimeNormalized},
+			{Rule: 0, Offset: 9, Token: "35:00", Kind: WarningTimeNormalized},
+		}},
+		{"normalized closing", "Mo 10:00-25:99", []Warning{{Rule: 0, Offset: 9, Token: "25:99", Kind: WarningTimeNormalized}}},
+		{"extended closing", "Mo 10:00-33:00", []Warning{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Lint(tt.args); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
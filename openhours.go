@@ -7,17 +7,70 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 )
 
 var (
 	weekDays = map[string]int{"su": 0, "mo": 1, "tu": 2, "we": 3, "th": 4, "fr": 5, "sa": 6}
+	months   = map[string]time.Month{"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6, "jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12}
 
 	// Errors
 	ErrInvalidFormat error = errors.New("invalid format")
+	ErrDated         error = errors.New("open hours depending on the date")
+	ErrExceptions    error = errors.New("exceptions cannot be written as an opening hours string")
 )
 
+// horizon is how far the open hours are looked at, in days, before giving up
+const horizon = 366 * 4
+
 // OpenHours ...
-type OpenHours []time.Time
+type OpenHours struct {
+	week  []time.Time              // open and close dates of a generic week, see newDate
+	rules []rule                   // only set when a rule depends on the date of the year
+	ex    map[Date][]time.Duration // open and close times from the start of the day, replacing the windows of the day
+	loc   *time.Location
+	ph    HolidayCalendar
+	sh    HolidayCalendar
+	dst   DSTPolicy
+}
+
+// rule is one sentence of the opening hours, restricted to some dates of the year
+type rule struct {
+	dates  []dateRange
+	ph, sh bool        // only on public or school holidays, replacing the hours of the day
+	off    bool        // closes the windows of week instead of opening them
+	week   []time.Time // unmerged, each pair starts on the week day it applies to
+}
+
+// dateRange is an inclusive range of days of the year, from may be after to (eg: Dec 24-Jan 02)
+type dateRange struct {
+	fromMonth time.Month
+	fromDay   int
+	toMonth   time.Month
+	toDay     int
+}
+
+func (r dateRange) match(month time.Month, day int) bool {
+	from := int(r.fromMonth)*100 + r.fromDay
+	to := int(r.toMonth)*100 + r.toDay
+	d := int(month)*100 + day
+	if from <= to {
+		return from <= d && d <= to
+	}
+	return d >= from || d <= to
+}
+
+func (r rule) match(month time.Month, day int) bool {
+	if len(r.dates) == 0 {
+		return true
+	}
+	for _, d := range r.dates {
+		if d.match(month, day) {
+			return true
+		}
+	}
+	return false
+}
 
 func newDate(day, hour, min, sec, nsec int, loc *time.Location) time.Time {
 	return time.Date(2017, 1, day, hour, min, sec, nsec, loc)
@@ -27,80 +80,453 @@ func newDateFromTime(t time.Time) time.Time {
 	return newDate(int(t.Weekday()), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
 }
 
-// Match returns true if the time t is in the open hours
+// weekStart is the unix time of the sunday midnight of newDate(0, ...) on the wall clock
+var weekStart = newDate(0, 0, 0, 0, 0, time.UTC).Unix()
+
+// weekOffset returns the wall clock time of t from the sunday midnight before it
+func weekOffset(t time.Time) time.Duration {
+	const day, week = 24 * 3600, 7 * 24 * 3600
+	_, offset := t.Zone()
+	secs := (t.Unix() + int64(offset) + 4*day) % week // the unix epoch is a thursday
+	if secs < 0 {
+		secs += week
+	}
+	return time.Duration(secs)*time.Second + time.Duration(t.Nanosecond())
+}
+
+// dateOffset is weekOffset for a date of a generic week read with the offset of its location,
+// which can go past saturday
+func dateOffset(d time.Time, offset int) time.Duration {
+	return time.Duration(d.Unix()+int64(offset)-weekStart)*time.Second + time.Duration(d.Nanosecond())
+}
+
+// weekZone returns the offset of the location of the generic week, the dates being in january there is no change of offset
+func weekZone(week []time.Time) int {
+	if len(week) == 0 {
+		return 0
+	}
+	_, offset := week[0].Zone()
+	return offset
+}
+
+// weekDay returns the day given to newDate, which can go past saturday
+func weekDay(t time.Time) int {
+	if t.Year() == 2016 { // newDate(0, ...) is the 31st of december
+		return 0
+	}
+	return t.Day()
+}
+
+func (o OpenHours) location() *time.Location {
+	if o.loc == nil {
+		return time.UTC
+	}
+	return o.loc
+}
+
+// dated returns true if the open hours depend on the date, by their rules or exceptions
+func (o OpenHours) dated() bool {
+	return o.rules != nil || o.ex != nil
+}
+
+// day returns the real open and close dates of the windows starting on the day of t
+func (o OpenHours) day(t time.Time) []time.Time {
+	day := []time.Time{}
+	if ex, exist := o.ex[DateOf(t)]; exist {
+		midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		for i := 0; i < len(ex)-1; i += 2 {
+			day = o.dst.window(day, midnight.Add(ex[i]), midnight.Add(ex[i+1]), t.Location())
+		}
+		return merge(day)
+	}
+	if o.rules == nil {
+		offset := weekZone(o.week)
+		for i := 0; i < len(o.week)-1; i += 2 {
+			if startsOn(dateOffset(o.week[i], offset), t.Weekday()) {
+				day = o.dst.window(day, wallOn(t, o.week[i]), wallOn(t, o.week[i+1]), t.Location())
+			}
+		}
+		return day
+	}
+	for _, r := range o.rules {
+		if !r.match(t.Month(), t.Day()) {
+			continue
+		}
+		if r.ph || r.sh {
+			if !(r.ph && isHoliday(o.ph, t) || r.sh && isHoliday(o.sh, t)) {
+				continue
+			}
+			if !r.off {
+				day = day[:0]
+			}
+		}
+		w := []time.Time{}
+		offset := weekZone(r.week)
+		for i := 0; i < len(r.week)-1; i += 2 {
+			if startsOn(dateOffset(r.week[i], offset), t.Weekday()) {
+				w = o.dst.window(w, wallOn(t, r.week[i]), wallOn(t, r.week[i+1]), t.Location())
+			}
+		}
+		if r.off {
+			day = subtract(merge(day), w)
+			continue
+		}
+		day = append(day, w...)
+	}
+	return merge(day)
+}
+
+// startsOn returns true if the window opening at the time from of a generic week (see dateOffset) starts on day,
+// a window opening past saturday ("sa 24:00-05:00") starting on saturday
+func startsOn(from time.Duration, day time.Weekday) bool {
+	d := int(from / (24 * time.Hour))
+	return d == int(day) || d == 7 && day == time.Saturday
+}
+
+// calendarDay is a day read by openOn and bounds, in the location of the open hours
+type calendarDay struct {
+	date    Date
+	weekday time.Weekday
+	sunday  time.Time // midnight of the sunday before on the wall clock, as a date in UTC
+	loc     *time.Location
+}
+
+func newCalendarDay(year int, month time.Month, day int, loc *time.Location) calendarDay {
+	midnight := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	year, month, day = midnight.Date()
+	weekday := midnight.Weekday()
+	return calendarDay{
+		date:    Date{year, month, day},
+		weekday: weekday,
+		sunday:  midnight.Add(-time.Duration(weekday) * 24 * time.Hour),
+		loc:     loc,
+	}
+}
+
+// noon returns the real date of noon of the day, given to the holiday calendars
+func (c calendarDay) noon() time.Time {
+	return time.Date(c.date.Year, c.date.Month, c.date.Day, 12, 0, 0, 0, c.loc)
+}
+
+// midnight returns the start of the day on the wall clock, as a date in UTC
+func (c calendarDay) midnight() time.Time {
+	return c.sunday.Add(time.Duration(c.weekday) * 24 * time.Hour)
+}
+
+// probe is a date looked for in the windows, with its wall clock time as a date in UTC
+type probe struct {
+	t, wall time.Time
+}
+
+func newProbe(t time.Time) probe {
+	_, offset := t.Zone()
+	return probe{t, t.UTC().Add(time.Duration(offset) * time.Second)}
+}
+
+// weekContains returns true if t is in one of the windows of the generic week starting on the day c
+func (p DSTPolicy) weekContains(week []time.Time, c calendarDay, t probe, loc *time.Location) bool {
+	offset := weekZone(week)
+	for i := 0; i < len(week)-1; i += 2 {
+		from := dateOffset(week[i], offset)
+		if startsOn(from, c.weekday) && p.contains(c.sunday.Add(from), c.sunday.Add(dateOffset(week[i+1], offset)), t, loc) {
+			return true
+		}
+	}
+	return false
+}
+
+// openOn returns true if t is in a window starting on the day c, as day gives them, without building them
+func (o OpenHours) openOn(c calendarDay, t probe) bool {
+	loc := o.location()
+	if ex, exist := o.ex[c.date]; exist {
+		midnight := c.midnight()
+		for i := 0; i < len(ex)-1; i += 2 {
+			if o.dst.contains(midnight.Add(ex[i]), midnight.Add(ex[i+1]), t, loc) {
+				return true
+			}
+		}
+		return false
+	}
+	if o.rules == nil {
+		return o.dst.weekContains(o.week, c, t, loc)
+	}
+	open := false
+	for _, r := range o.rules {
+		if !r.match(c.date.Month, c.date.Day) || r.off && !open {
+			continue
+		}
+		in := o.dst.weekContains(r.week, c, t, loc)
+		if !r.ph && !r.sh {
+			if in {
+				open = !r.off
+			}
+			continue
+		}
+		// a holiday rule replaces the hours of the day, the calendar is only asked when that changes something
+		if r.off && in && open || !r.off && in != open {
+			if r.ph && isHoliday(o.ph, c.noon()) || r.sh && isHoliday(o.sh, c.noon()) {
+				open = in && !r.off
+			}
+		}
+	}
+	return open
+}
+
+// openAt returns true if t is in a window of its day or of the week before, like windows, as a window can last for days
+func (o OpenHours) openAt(t time.Time) bool {
+	loc := o.location()
+	t = t.In(loc)
+	year, month, day := t.Date()
+	p := newProbe(t)
+	for days, back := 0, o.lookBack(); days <= back; days++ {
+		if o.openOn(newCalendarDay(year, month, day-days, loc), p) {
+			return true
+		}
+	}
+	return false
+}
+
+// lookBack returns how many days before t a window reaching t may start, at most a week like windows
+func (o OpenHours) lookBack() int {
+	longest := time.Duration(0)
+	week := func(w []time.Time) {
+		for i := 0; i < len(w)-1; i += 2 {
+			longest = max(longest, w[i+1].Sub(w[i]))
+		}
+	}
+	week(o.week)
+	for _, r := range o.rules {
+		if !r.off {
+			week(r.week)
+		}
+	}
+	for _, ex := range o.ex {
+		for i := 0; i < len(ex)-1; i += 2 {
+			longest = max(longest, ex[i+1]-ex[i])
+		}
+	}
+	// an hour more for the changes of offset
+	return min(7, int((longest+time.Hour)/(24*time.Hour))+1)
+}
+
+// bounds calls f with the real open and close dates of the windows which may start on the day c and end after t:
+// the ones of the exceptions of the day, or of the rules matching it, holidays or not
+func (o OpenHours) bounds(c calendarDay, t probe, f func(b time.Time)) {
+	loc := o.location()
+	window := func(from, to time.Time) {
+		if to.Before(t.wall.Add(-6 * time.Hour)) { // see DSTPolicy.contains
+			return
+		}
+		if start, end, ok := o.dst.bounds(from, to, loc); ok {
+			f(start)
+			f(end)
+		}
+	}
+	if ex, exist := o.ex[c.date]; exist {
+		midnight := c.midnight()
+		for i := 0; i < len(ex)-1; i += 2 {
+			window(midnight.Add(ex[i]), midnight.Add(ex[i+1]))
+		}
+		return
+	}
+	week := func(w []time.Time) {
+		offset := weekZone(w)
+		for i := 0; i < len(w)-1; i += 2 {
+			if from := dateOffset(w[i], offset); startsOn(from, c.weekday) {
+				window(c.sunday.Add(from), c.sunday.Add(dateOffset(w[i+1], offset)))
+			}
+		}
+	}
+	if o.rules == nil {
+		week(o.week)
+		return
+	}
+	for _, r := range o.rules {
+		if r.match(c.date.Month, c.date.Day) {
+			week(r.week)
+		}
+	}
+}
+
+// windows returns the real open and close dates of the windows starting between the days of from and to.
+// The week before from is looked at too, as a window can last for days.
+func (o OpenHours) windows(from, to time.Time) []time.Time {
+	loc := o.location()
+	from, to = from.In(loc), to.In(loc)
+	w := []time.Time{}
+	last := time.Date(to.Year(), to.Month(), to.Day(), 12, 0, 0, 0, loc)
+	for d := time.Date(from.Year(), from.Month(), from.Day()-7, 12, 0, 0, 0, loc); !d.After(last); d = d.AddDate(0, 0, 1) {
+		w = append(w, o.day(d)...)
+	}
+	return merge(w)
+}
+
+// next returns true if t is open and the date of the next change, looking up to a few years ahead.
+// The changes are at the open and close dates of the windows, the first one after t with another state being the next one.
+func (o OpenHours) next(t time.Time) (bool, time.Time) {
+	loc := o.location()
+	t = t.In(loc)
+	isOpen := o.openAt(t)
+	next, found := time.Time{}, false
+	check := func(b time.Time) {
+		if b.After(t) && (!found || b.Before(next)) && o.openAt(b) != isOpen {
+			next, found = b, true
+		}
+	}
+	year, month, day := t.Date()
+	p := newProbe(t)
+	for days := -o.lookBack(); days <= horizon; days++ {
+		o.bounds(newCalendarDay(year, month, day+days, loc), p, check)
+		// the windows of the following days start after their midnight
+		if found && next.Before(time.Date(year, month, day+days+1, 0, 0, 0, 0, loc)) {
+			return isOpen, next
+		}
+	}
+	return isOpen, t.Add(time.Duration(horizon) * 24 * time.Hour)
+}
+
+// prev returns true if t is open and the date of the last change, looking up to a few years back
+func (o OpenHours) prev(t time.Time) (bool, time.Time) {
+	for days := 7; ; days *= 2 {
+		start := t.AddDate(0, 0, -days)
+		w := o.windows(start, t)
+		i := matchIndex(w, t)
+		// the first window may go on with the windows of the previous days
+		if i > 0 && w[i-1].After(start) {
+			return i%2 == 1, w[i-1]
+		}
+		if days > horizon { // never or always open, like next
+			return i%2 == 1, t.Add(-time.Duration(horizon) * 24 * time.Hour)
+		}
+	}
+}
+
+// Match returns true if the time t is in the open hours, read on the wall clock of the location of o
 func (o OpenHours) Match(t time.Time) bool {
-	t = newDateFromTime(t)
-	i := o.matchIndex(t)
-	return i%2 == 1
+	if o.daily() {
+		return o.openAt(t)
+	}
+	return openOffset(o.week, weekZone(o.week), weekOffset(t.In(o.location())))
 }
 
 // matchIndex returns the index of the next open hour
-func (o OpenHours) matchIndex(t time.Time) int {
-	i := 0
-	for ; i < len(o); i++ {
-		if o[i].After(t) {
-			break
-		}
+func matchIndex(o []time.Time, t time.Time) int {
+	return sort.Search(len(o), func(i int) bool { return o[i].After(t) })
+}
+
+// offsetIndex is matchIndex for the wall clock time d of a generic week, without building a date
+func offsetIndex(week []time.Time, offset int, d time.Duration) int {
+	return sort.Search(len(week), func(i int) bool { return dateOffset(week[i], offset) > d })
+}
+
+// weekLength is the wall clock length of a generic week
+const weekLength = 7 * 24 * time.Hour
+
+// openOffset returns true if the wall clock time d of a generic week is in a window,
+// or in the part of a window of the week before going past saturday
+func openOffset(week []time.Time, offset int, d time.Duration) bool {
+	if offsetIndex(week, offset, d)%2 == 1 {
+		return true
+	}
+	return len(week) > 0 && dateOffset(week[len(week)-1], offset) > weekLength && offsetIndex(week, offset, d+weekLength)%2 == 1
+}
+
+// nextOffset returns the wall clock duration from the time d of a generic week to the next open or close date,
+// the ones of the week before going past saturday and of the week after included
+func nextOffset(week []time.Time, offset int, d time.Duration) time.Duration {
+	next := dateOffset(week[0], offset) + weekLength - d
+	if i := offsetIndex(week, offset, d); i < len(week) {
+		next = min(next, dateOffset(week[i], offset)-d)
+	}
+	if i := offsetIndex(week, offset, d+weekLength); i < len(week) {
+		next = min(next, dateOffset(week[i], offset)-weekLength-d)
 	}
-	return i
+	return next
 }
 
 // NextDur returns true if t is in the open hours and the duration until it closes
 // else it returns false if t is in the closed hours and the duration until it opens
 func (o OpenHours) NextDur(t time.Time) (bool, time.Duration) {
-	current := newDateFromTime(t)
-	i := o.matchIndex(current)
-	isOpen := i%2 == 1 // uneven -> next time is a closing time
-	if i == len(o) {   // end of week, wrap around
-		i = 0
+	if o.daily() {
+		isOpen, next := o.next(t)
+		return isOpen, next.Sub(t)
 	}
-	next := o[i]
-	if current.After(next) { // we wrapped, set days to end of week
-		next = next.AddDate(0, 0, 7)
+	if len(o.week) == 0 { // never open (eg: "su off"), there is no window to wrap around to
+		return false, time.Duration(horizon) * 24 * time.Hour
+	}
+	t = t.In(o.location())
+	current, offset := weekOffset(t), weekZone(o.week)
+	isOpen := openOffset(o.week, offset, current)
+	// a window going past saturday may go on with the ones of sunday, the change is the first date with another state
+	diff := time.Duration(0)
+	for range len(o.week) + 1 {
+		diff += nextOffset(o.week, offset, (current+diff)%weekLength)
+		if openOffset(o.week, offset, (current+diff)%weekLength) != isOpen {
+			return isOpen, tzDiff(diff, t)
+		}
 	}
-	return isOpen, tzDiff(next, current, t)
+	return isOpen, time.Duration(horizon) * 24 * time.Hour // always open
 }
 
-// tzDiff calculate diff between a and b and add it to t, taking in account eventual tz changes
-func tzDiff(a, b, t time.Time) time.Duration {
-	diff := a.Sub(b)
+// tzDiff adds the wall clock duration diff to t, taking in account eventual tz changes.
+// A time which does not exist is read with the offset before the change, like DSTShift does.
+func tzDiff(diff time.Duration, t time.Time) time.Duration {
 	_, offset := t.Zone()
 	_, newOffset := t.Add(diff).Zone()
-	return diff + time.Duration(offset-newOffset)*time.Second
+	fixed := diff + time.Duration(offset-newOffset)*time.Second
+	if _, o := t.Add(fixed).Zone(); o != newOffset { // in the gap, the offset before the change is the smallest
+		return diff + time.Duration(offset-min(offset, newOffset))*time.Second
+	}
+	return fixed
 }
 
 // When returns the date where the duration can be done in one go during open hours
 func (o OpenHours) When(t time.Time, d time.Duration) *time.Time {
-	x := newDateFromTime(t)
-	i := o.matchIndex(x)
-	var found *time.Time
-	if i%2 == 1 {
-		newO := x.Add(d)
-		// log.Println(x, newO, o[i], newO.Before(o[i]) || newO.Equal(o[i]), i, o)
-		if newO.Before(o[i]) || newO.Equal(o[i]) {
-			found = &x
-		} else {
-			i += 2
-		}
-	} else {
-		i++
+	if o.daily() {
+		return o.whenDated(t, d)
 	}
-	for max := i + len(o); i < max && found == nil; i += 2 {
-		newI := i % len(o)
-		newO := o[newI-1].Add(d)
-		if newO.Before(o[newI]) || newO.Equal(o[newI]) {
-			found = &o[newI-1]
+	local := t.In(o.location())
+	x := newDateFromTime(local)
+	// the weeks before and after, for the windows going past saturday and the wrap around
+	week := make([]time.Time, 0, 3*len(o.week))
+	for _, days := range []int{-7, 0, 7} {
+		for _, date := range o.week {
+			week = append(week, date.AddDate(0, 0, days))
 		}
 	}
-	if found == nil {
-		return found
+	week = merge(week)
+	for i := 1; i < len(week); i += 2 {
+		start := week[i-1]
+		if start.Before(x) {
+			start = x
+		}
+		// the real dates, for the windows over a change of offset
+		f, end := t.Add(tzDiff(start.Sub(x), local)), t.Add(tzDiff(week[i].Sub(x), local))
+		if f.Add(d).After(end) {
+			continue
+		}
+		return &f
 	}
-	if x.After(*found) {
-		z := found.AddDate(0, 0, 7)
-		found = &z
+	return nil
+}
+
+// whenDated is When for open hours depending on the date of the year
+func (o OpenHours) whenDated(t time.Time, d time.Duration) *time.Time {
+	for days := 7; days <= horizon; days *= 2 {
+		w := o.windows(t, t.AddDate(0, 0, days))
+		for i := 1; i < len(w); i += 2 {
+			start := w[i-1]
+			if start.Before(t) {
+				start = t
+			}
+			if start.Add(d).After(w[i]) {
+				continue
+			}
+			f := start.In(t.Location())
+			return &f
+		}
 	}
-	f := t.Add(tzDiff(*found, x, t))
-	return &f
+	return nil
 }
 
 // NextDate uses nextDur to gives the date of interest
@@ -109,9 +535,46 @@ func (o OpenHours) NextDate(t time.Time) (bool, time.Time) {
 	return b, t.Add(dur)
 }
 
+// PrevDur returns true if t is in the open hours and the duration since it opened
+// else it returns false if t is in the closed hours and the duration since it closed
+func (o OpenHours) PrevDur(t time.Time) (bool, time.Duration) {
+	if o.daily() {
+		isOpen, prev := o.prev(t)
+		return isOpen, t.Sub(prev)
+	}
+	if len(o.week) == 0 { // never open, like NextDur
+		return false, time.Duration(horizon) * 24 * time.Hour
+	}
+	local := t.In(o.location())
+	current := newDateFromTime(local)
+	week := make([]time.Time, 0, 2*len(o.week))
+	for _, d := range o.week { // the week before, for the windows going past saturday and the wrap around
+		week = append(week, d.AddDate(0, 0, -7))
+	}
+	week = merge(append(week, o.week...))
+	if len(week) == 2 && !week[1].Before(week[0].AddDate(0, 0, 14)) { // always open, the two weeks being one window
+		return true, time.Duration(horizon) * 24 * time.Hour
+	}
+	i := matchIndex(week, current)
+	return i%2 == 1, -tzDiff(week[i-1].Sub(current), local)
+}
+
+// PrevDate uses PrevDur to give the date of the last change
+func (o OpenHours) PrevDate(t time.Time) (bool, time.Time) {
+	b, dur := o.PrevDur(t)
+	return b, t.Add(-dur)
+}
+
 func (o OpenHours) Add(from, to time.Time) OpenHours {
-	o = append(o, newDateFromTime(from), newDateFromTime(to))
-	o = merge(o)
+	if o.loc == nil {
+		o.loc = from.Location()
+	}
+	pair := []time.Time{newDateFromTime(from.In(o.loc)), newDateFromTime(to.In(o.loc))}
+	if o.rules != nil {
+		o.rules = append(o.rules[:len(o.rules):len(o.rules)], rule{week: pair})
+		return o
+	}
+	o.week = merge(append(o.week[:len(o.week):len(o.week)], pair...))
 	return o
 }
 
@@ -119,24 +582,60 @@ var weekdays = map[int]string{0: "Sunday", 1: "Monday", 2: "Tuesday", 3: "Wednes
 
 func (o OpenHours) String() []string {
 	str := []string{}
-	if len(o) == 0 {
+	if len(o.week) == 0 {
 		return str
 	}
-	for i := 1; i <= len(o)-1; i += 2 {
-		str = append(str, fmt.Sprintf("%s %s - %s", weekdays[o[i-1].Day()], o[i-1].Format("15:04"), o[i].Format("15:04")))
+	for i := 1; i <= len(o.week)-1; i += 2 {
+		str = append(str, fmt.Sprintf("%s %s - %s", weekdays[weekDay(o.week[i-1])], o.week[i-1].Format("15:04"), o.week[i].Format("15:04")))
 	}
 	return str
 }
 
 func cleanStr(str string) string {
-	clean := strings.TrimSpace(str)
-	clean = strings.Join(strings.Fields(clean), " ")
-	clean = strings.ToLower(clean)
-	clean = strings.Replace(clean, " ,", ",", -1)
-	clean = strings.Replace(clean, ", ", ",", -1)
+	clean, _ := cleanStrOffsets(str)
 	return clean
 }
 
+// cleanStrOffsets lowers the case and removes the extra spaces of str,
+// giving the offset in str of each byte of the clean string
+func cleanStrOffsets(str string) (string, []int) {
+	clean := []byte{}
+	offsets := []int{}
+	space := -1 // offset of the spaces not written yet
+	for i, r := range str {
+		if unicode.IsSpace(r) {
+			if space < 0 {
+				space = i
+			}
+			continue
+		}
+		if space >= 0 && len(clean) > 0 && r != ',' && clean[len(clean)-1] != ',' {
+			clean = append(clean, ' ')
+			offsets = append(offsets, space)
+		}
+		space = -1
+		for _, b := range []byte(string(unicode.ToLower(r))) {
+			clean = append(clean, b)
+			offsets = append(offsets, i)
+		}
+	}
+	return string(clean), offsets
+}
+
+// fields is strings.Fields for a clean string, giving the offset of each field
+func fields(str string) ([]string, []int) {
+	strs, offsets := []string{}, []int{}
+	offset := 0
+	for _, s := range strings.Split(str, " ") {
+		if s != "" {
+			strs = append(strs, s)
+			offsets = append(offsets, offset)
+		}
+		offset += len(s) + 1
+	}
+	return strs, offsets
+}
+
 func simplifyDays(str string) []int {
 	simple := []int{}
 	days := map[int]struct{}{}
@@ -173,6 +672,50 @@ func simplifyDays(str string) []int {
 	return simple
 }
 
+// strictTime returns the seconds since midnight of a "hh:mm" or "hh:mm:ss" time, up to maxHour:00
+func strictTime(str string, maxHour int) (int, bool) {
+	strs := strings.Split(str, ":")
+	if len(strs) < 2 || len(strs) > 3 {
+		return 0, false
+	}
+	secs := 0
+	for i, s := range strs {
+		if len(s) != 2 && (i > 0 || len(s) != 1) || strings.Trim(s, "0123456789") != "" {
+			return 0, false
+		}
+		n, _ := strconv.Atoi(s)
+		if i > 0 && n > 59 {
+			return 0, false
+		}
+		secs = secs*60 + n
+	}
+	if len(strs) == 2 {
+		secs *= 60
+	}
+	if secs/3600 == 24 && secs%3600 != 0 { // simplifyTime reads 24:30 as 00:00
+		return 0, false
+	}
+	return secs, secs <= maxHour*3600
+}
+
+// checkDays returns an error for each part of a day list which is not a week day, a range of week days, "ph" or "sh"
+func checkDays(str string) *ParseError {
+	offset := 0
+	for _, str := range strings.Split(str, ",") {
+		ok := str == "ph" || str == "sh"
+		if strs := strings.Split(str, "-"); len(strs) <= 2 {
+			_, from := weekDays[strs[0]]
+			_, to := weekDays[strs[len(strs)-1]]
+			ok = ok || from && to
+		}
+		if !ok {
+			return parseError(ReasonUnknownWeekday, str, offset)
+		}
+		offset += len(str) + 1
+	}
+	return nil
+}
+
 func simplifyTime(str string) (int, int, int) {
 	hour, min := 0, 0
 	strs := strings.Split(str, ":")
@@ -194,91 +737,325 @@ func simplifyTime(str string) (int, int, int) {
 	return hour, min, sec
 }
 
-func new(str string, loc *time.Location) (OpenHours, error) {
+// simplifyDate returns the month and day of "dec 24", "dec" or "24", zero if not given
+func simplifyDate(str string) (time.Month, int, bool) {
+	month, day := time.Month(0), 0
+	strs := strings.Fields(str)
+	if len(strs) > 0 {
+		if m, exist := months[strs[0]]; exist {
+			month = m
+			strs = strs[1:]
+		}
+	}
+	if len(strs) > 1 {
+		return 0, 0, false
+	}
+	if len(strs) == 1 {
+		d, err := strconv.Atoi(strs[0])
+		if err != nil || d < 1 || d > 31 {
+			return 0, 0, false
+		}
+		day = d
+	}
+	return month, day, month != 0 || day != 0
+}
+
+// simplifyDates returns the date ranges of selectors like "jun-aug", "dec 25" or "dec 24-jan 02,jul"
+func simplifyDates(str string) ([]dateRange, *ParseError) {
+	dates := []dateRange{}
+	if str == "" {
+		return dates, nil
+	}
+	offset := 0
+	for _, str := range strings.Split(str, ",") {
+		bad := parseError(ReasonBadDate, str, offset)
+		offset += len(str) + 1
+		strs := strings.Split(str, "-")
+		if len(strs) > 2 {
+			return nil, bad
+		}
+		month, day, ok := simplifyDate(strs[0])
+		if !ok || month == 0 {
+			return nil, bad
+		}
+		r := dateRange{fromMonth: month, fromDay: day, toMonth: month, toDay: day}
+		if day == 0 { // whole month
+			r.fromDay, r.toDay = 1, 31
+		}
+		if len(strs) == 2 {
+			toMonth, toDay, ok := simplifyDate(strs[1])
+			if !ok {
+				return nil, bad
+			}
+			if toMonth == 0 { // "dec 24-26"
+				if day == 0 {
+					return nil, bad
+				}
+				toMonth = month
+			}
+			if toDay == 0 {
+				toDay = 31
+			}
+			r.toMonth, r.toDay = toMonth, toDay
+		}
+		dates = append(dates, r)
+	}
+	return dates, nil
+}
+
+// isDate returns true if str is part of a date selector, numbers only count after a month.
+// A month is a whole token or followed by a day or a range ("dec24", "apr-oct"), so that "mardi" is a day.
+func isDate(str string, afterMonth bool) bool {
+	if len(str) >= 3 {
+		if _, exist := months[str[:3]]; exist && (len(str) == 3 || strings.ContainsRune("-,0123456789", rune(str[3]))) {
+			return true
+		}
+	}
+	return afterMonth && str[0] >= '0' && str[0] <= '9' && !strings.Contains(str, ":")
+}
+
+// simplifyHolidays returns true for public and school holidays if "ph" or "sh" are in the day list
+func simplifyHolidays(str string) (bool, bool) {
+	ph, sh := false, false
+	for _, str := range strings.Split(str, ",") {
+		ph = ph || str == "ph"
+		sh = sh || str == "sh"
+	}
+	return ph, sh
+}
+
+// isOff returns true for the "off" and "closed" rule modifiers
+func isOff(str string) bool {
+	return str == "off" || str == "closed"
+}
+
+// simplifyWindows returns the open and close dates of the time windows (eg: "10:00-12:00,13:00-17:00") on each day
+// In strict mode, times must be valid and can only go past 24:00 when closing the next day.
+func simplifyWindows(days []int, str string, loc *time.Location, strict bool) ([]time.Time, *ParseError) {
+	week := []time.Time{}
+	offset := 0
+	for _, str := range strings.Split(str, ",") {
+		times := strings.Split(str, "-")
+		switch {
+		case len(times) < 2:
+			return nil, parseError(ReasonMissingRangeEnd, str, offset)
+		case len(times) > 2:
+			return nil, parseError(ReasonBadTimeRange, str, offset)
+		}
+		if strict {
+			from, ok := strictTime(times[0], 24)
+			if !ok {
+				return nil, parseError(ReasonBadTime, times[0], offset)
+			}
+			if to, ok := strictTime(times[1], 48); !ok || to > 24*3600 && to-24*3600 >= from {
+				return nil, parseError(ReasonBadTime, times[1], offset+len(times[0])+1)
+			}
+		}
+		offset += len(str) + 1
+		hourFrom, minFrom, secFrom := simplifyTime(times[0])
+		hourTo, minTo, secTo := simplifyTime(times[1])
+		for _, day := range days {
+			fromDate := newDate(day, hourFrom, minFrom, secFrom, 0, loc)
+			if hourFrom > hourTo { // closing after midnight
+				day++
+			}
+			week = append(week, fromDate, newDate(day, hourTo, minTo, secTo, 0, loc))
+		}
+	}
+	return week, nil
+}
+
+// part is a piece of a clean rule and its offset in the rule
+type part struct {
+	str    string
+	offset int
+}
+
+// sentence is a clean rule split in its parts, a missing part has an empty str
+type sentence struct {
+	dates, days, times part
+	off                bool
+	rest               []part // anything after the "off" modifier or the times
+}
+
+func splitRule(str string) sentence {
+	strs, offsets := fields(str)
+	s := sentence{}
+	i := 0
+	for i < len(strs) && isDate(strs[i], i > 0) {
+		i++
+	}
+	if i > 0 {
+		s.dates = part{strings.Join(strs[:i], " "), offsets[0]}
+	}
+	strs, offsets = strs[i:], offsets[i:]
+	if len(strs) > 0 && !strings.Contains(strs[0], ":") && !isOff(strs[0]) {
+		s.days = part{strs[0], offsets[0]}
+		strs, offsets = strs[1:], offsets[1:]
+	}
+	if len(strs) > 0 && !isOff(strs[0]) {
+		s.times = part{strs[0], offsets[0]}
+		strs, offsets = strs[1:], offsets[1:]
+	}
+	if len(strs) > 0 && isOff(strs[0]) {
+		s.off = true
+		strs, offsets = strs[1:], offsets[1:]
+	}
+	for i := range strs {
+		s.rest = append(s.rest, part{strs[i], offsets[i]})
+	}
+	return s
+}
+
+// parseRule returns the rules of a clean sentence (weekly and holidays), errors are positioned in str.
+// In strict mode, unknown days, invalid times and trailing tokens are errors instead of being ignored.
+func parseRule(str string, loc *time.Location, strict bool) ([]rule, *ParseError) {
+	s := splitRule(str)
+	dates, err := simplifyDates(s.dates.str)
+	if err != nil {
+		return nil, err.shift(s.dates.offset)
+	}
+	days, ph, sh := []int{0, 1, 2, 3, 4, 5, 6}, false, false
+	if s.days.str != "" {
+		if err := checkDays(s.days.str); strict && err != nil {
+			return nil, err.shift(s.days.offset)
+		}
+		days = simplifyDays(s.days.str)
+		ph, sh = simplifyHolidays(s.days.str)
+	}
+	if s.times.str == "" && !s.off {
+		return nil, parseError(ReasonMissingTimes, strings.TrimSpace(str), len(str)-len(strings.TrimLeft(str, " ")))
+	}
+	if strict && len(s.rest) > 0 {
+		return nil, parseError(ReasonUnexpectedToken, s.rest[0].str, s.rest[0].offset)
+	}
+	times := s.times.str
+	if times == "" { // "su off" closes the whole day
+		times = "00:00-24:00"
+	}
+	week, err := simplifyWindows(days, times, loc, strict)
+	if err != nil {
+		return nil, err.shift(s.times.offset)
+	}
+	rules := []rule{}
+	if len(days) > 0 || !ph && !sh {
+		rules = append(rules, rule{dates: dates, off: s.off, week: week})
+	}
+	if ph || sh {
+		week, _ := simplifyWindows([]int{0, 1, 2, 3, 4, 5, 6}, times, loc, strict)
+		rules = append(rules, rule{dates: dates, ph: ph, sh: sh, off: s.off, week: week})
+	}
+	return rules, nil
+}
+
+func new(str string, loc *time.Location, strict bool) (OpenHours, error) {
 	if loc == nil {
 		loc = time.UTC
 	}
-	o := []time.Time{}
+	o := OpenHours{week: []time.Time{}, loc: loc}
+	rules := []rule{}
+	dated := false
 	if len(str) > 0 && str[len(str)-1] == ';' {
 		str = str[:len(str)-1]
 	}
 	if str == "" {
 		str = "su-sa 00:00-24:00"
 	}
-	for _, str := range strings.Split(cleanStr(str), ";") {
-		strs := strings.Fields(str)
-		if len(strs) < 2 {
-			return nil, ErrInvalidFormat
+	clean, offsets := cleanStrOffsets(str)
+	offset := 0
+	for n, sentence := range strings.Split(clean, ";") {
+		rs, err := parseRule(sentence, loc, strict)
+		if err != nil {
+			return OpenHours{}, err.shift(offset).in(str, offsets, n)
 		}
-		days := simplifyDays(strs[0])
-		for _, str := range strings.Split(strs[1], ",") {
-			times := strings.Split(str, "-")
-			if len(times) != 2 {
-				return nil, ErrInvalidFormat
-			}
-			hourFrom, minFrom, secFrom := simplifyTime(times[0])
-			hourTo, minTo, secTo := simplifyTime(times[1])
-			for _, day := range days {
-				fromDate := newDate(day, hourFrom, minFrom, secFrom, 0, loc)
-				if hourFrom > hourTo { // closing after midnight
-					day++
-				}
-				o = append(o, fromDate, newDate(day, hourTo, minTo, secTo, 0, loc))
+		offset += len(sentence) + 1
+		for _, r := range rs {
+			rules = append(rules, r)
+			dated = dated || len(r.dates) > 0 || r.ph || r.sh
+			switch {
+			case r.ph || r.sh:
+			case r.off:
+				o.week = subtract(merge(o.week), r.week)
+			default:
+				o.week = append(o.week, r.week...)
 			}
 		}
 	}
+	if dated {
+		o.week, o.rules = nil, rules
+		return o, nil
+	}
+	o.week = merge(o.week)
 	return o, nil
 }
 
-func merge4(o ...time.Time) (bool, []time.Time) {
-	for i := 0; i < len(o)-1; i++ {
-		if o[i].After(o[i+1]) || o[i].Equal(o[i+1]) {
-			sort.Slice(o, func(i, j int) bool {
-				return o[i].Before(o[j])
-			})
-			return true, []time.Time{o[0], o[len(o)-1]}
+// merge sorts the pairs of open and close dates and joins the ones overlapping or touching
+func merge(o []time.Time) []time.Time {
+	pairs := make([][2]time.Time, 0, len(o)/2)
+	for i := 0; i < len(o)-1; i += 2 {
+		if o[i+1].After(o[i]) {
+			pairs = append(pairs, [2]time.Time{o[i], o[i+1]})
 		}
 	}
-	return false, nil
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i][0].Before(pairs[j][0])
+	})
+	merged := []time.Time{}
+	for _, p := range pairs {
+		if n := len(merged); n > 0 && !p[0].After(merged[n-1]) {
+			if p[1].After(merged[n-1]) {
+				merged[n-1] = p[1]
+			}
+			continue
+		}
+		merged = append(merged, p[0], p[1])
+	}
+	return merged
 }
 
-func merge(o []time.Time) []time.Time {
-	sort.SliceStable(o, func(i, j int) bool {
-		if o[i].Day() == o[j].Day() {
-			return o[i].Hour() < o[j].Hour()
-		}
-		return o[i].Day() < o[j].Day()
-	})
-	for i := 0; i < len(o); i += 2 {
-		for j := i + 2; j < len(o); j += 2 {
-			perform, res := merge4(o[i], o[i+1], o[j], o[j+1])
-			if !perform {
+// subtract removes the pairs of off from the merged pairs of o
+func subtract(o, off []time.Time) []time.Time {
+	off = merge(off)
+	res := []time.Time{}
+	for i := 0; i < len(o)-1; i += 2 {
+		from, to := o[i], o[i+1]
+		for j := 0; j < len(off)-1 && from.Before(to); j += 2 {
+			if !off[j+1].After(from) || !off[j].Before(to) { // no overlap
 				continue
 			}
-			o[i], o[i+1] = res[0], res[1]
-			o = append(o[:j], o[j+2:]...)
-			i -= 2
-			break
+			if off[j].After(from) {
+				res = append(res, from, off[j])
+			}
+			from = off[j+1]
+		}
+		if from.Before(to) {
+			res = append(res, from, to)
 		}
 	}
-	return o
+	return res
 }
 
 // New returns a new instance of an openhours.
 // If loc is nil, UTC is used.
 func New(str string, loc *time.Location) (OpenHours, error) {
-	o, err := new(str, loc)
-	return merge(o), err
+	return new(str, loc, false)
 }
 
 // NewMust returns a new instance of an openhours or panics on error
 // If loc is nil, UTC is used.
 func NewMust(str string, loc *time.Location) OpenHours {
-	o, err := new(str, loc)
+	o, err := new(str, loc, false)
 	if err != nil {
 		panic(err)
 	}
-	return merge(o)
+	return o
+}
+
+// NewStrict returns a new instance of an openhours, failing with a ParseError
+// on what New ignores: unknown days, invalid or out of range times and trailing tokens.
+// If loc is nil, UTC is used.
+func NewStrict(str string, loc *time.Location) (OpenHours, error) {
+	return new(str, loc, true)
 }
 
 // NewLocal returns a new instance of an openhours with local timezone
@@ -1,9 +1,13 @@
 package openhours
 
 import (
+	"errors"
+	"fmt"
+	"math/rand"
 	"reflect"
 	"runtime/debug"
 	"slices"
+	"strings"
 	"testing"
 	"time"
 )
@@ -222,25 +226,25 @@ func TestNew(t *testing.T) {
 		args2 *time.Location
 		want  OpenHours
 	}{
-		{"empty", "", l, []time.Time{newDate(0, 0, 0, 0, 0, l), newDate(7, 0, 0, 0, 0, l)}},
-		{"empty ;", ";", l, []time.Time{newDate(0, 0, 0, 0, 0, l), newDate(7, 0, 0, 0, 0, l)}},
-		{"all day ;", "su-sa 00:00-24:00;", l, []time.Time{newDate(0, 0, 0, 0, 0, l), newDate(7, 0, 0, 0, 0, l)}},
-		{"empty and no tz", "", nil, []time.Time{newDate(0, 0, 0, 0, 0, time.UTC), newDate(7, 0, 0, 0, 0, time.UTC)}},
+		{"empty", "", l, OpenHours{loc: l, week: []time.Time{newDate(0, 0, 0, 0, 0, l), newDate(7, 0, 0, 0, 0, l)}}},
+		{"empty ;", ";", l, OpenHours{loc: l, week: []time.Time{newDate(0, 0, 0, 0, 0, l), newDate(7, 0, 0, 0, 0, l)}}},
+		{"all day ;", "su-sa 00:00-24:00;", l, OpenHours{loc: l, week: []time.Time{newDate(0, 0, 0, 0, 0, l), newDate(7, 0, 0, 0, 0, l)}}},
+		{"empty and no tz", "", nil, OpenHours{loc: time.UTC, week: []time.Time{newDate(0, 0, 0, 0, 0, time.UTC), newDate(7, 0, 0, 0, 0, time.UTC)}}},
 		{"order on same sentence", "mo,tu 10:00-11:00", nil, NewMust("tu,mo 10:00-11:00", nil)},
 		{"order on different sentences", "mo 10:00-11:00;tu 10:00-12:00", nil, NewMust("tu 10:00-12:00;mo 10:00-11:00", nil)},
 		{"complex = simple", "su-sa 00:00-12:00,12:00-24:00", l, NewMust("", l)},
 		{"complex = simple", "su-sa 00:00-12:00;su-sa 12:00-24:00", l, NewMust("", l)},
 		{"time windows order does not matter anymore", "mo-su 00:00-24:00", l, NewMust("", l)},
-		{"one day", "mo 10:00-15:00", l, []time.Time{newDate(1, 10, 0, 0, 0, l), newDate(1, 15, 0, 0, 0, l)}},
-		{"two days", "mo 10:00-15:00;fr 08:00-14:00", l, []time.Time{newDate(1, 10, 0, 0, 0, l), newDate(1, 15, 0, 0, 0, l), newDate(5, 8, 0, 0, 0, l), newDate(5, 14, 0, 0, 0, l)}},
-		{"week with break", "Tu-Th 10:30-13:00,14:00-24:00", l, []time.Time{
+		{"one day", "mo 10:00-15:00", l, OpenHours{loc: l, week: []time.Time{newDate(1, 10, 0, 0, 0, l), newDate(1, 15, 0, 0, 0, l)}}},
+		{"two days", "mo 10:00-15:00;fr 08:00-14:00", l, OpenHours{loc: l, week: []time.Time{newDate(1, 10, 0, 0, 0, l), newDate(1, 15, 0, 0, 0, l), newDate(5, 8, 0, 0, 0, l), newDate(5, 14, 0, 0, 0, l)}}},
+		{"week with break", "Tu-Th 10:30-13:00,14:00-24:00", l, OpenHours{loc: l, week: []time.Time{
 			newDate(2, 10, 30, 0, 0, l), newDate(2, 13, 0, 0, 0, l),
 			newDate(2, 14, 0, 0, 0, l), newDate(2, 24, 0, 0, 0, l),
 			newDate(3, 10, 30, 0, 0, l), newDate(3, 13, 0, 0, 0, l),
 			newDate(3, 14, 0, 0, 0, l), newDate(3, 24, 0, 0, 0, l),
 			newDate(4, 10, 30, 0, 0, l), newDate(4, 13, 0, 0, 0, l),
 			newDate(4, 14, 0, 0, 0, l), newDate(4, 24, 0, 0, 0, l),
-		}},
+		}}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -282,6 +286,70 @@ func TestOpenHours_NextDate(t *testing.T) {
 	}
 }
 
+func TestOpenHours_PrevDur(t *testing.T) {
+	tests := []struct {
+		name  string
+		oh    string
+		args  time.Time
+		want  bool
+		want1 time.Duration
+	}{
+		{"open", "mo-fr 09:00-17:00", time.Date(2019, 3, 6, 10, 0, 0, 0, l), true, time.Hour},
+		{"at start", "mo-fr 09:00-17:00", time.Date(2019, 3, 6, 9, 0, 0, 0, l), true, 0},
+		{"closed", "mo-fr 09:00-17:00", time.Date(2019, 3, 6, 18, 0, 0, 0, l), false, time.Hour},
+		{"after the weekend", "mo-fr 09:00-17:00", time.Date(2019, 3, 11, 8, 0, 0, 0, l), false, 63 * time.Hour},
+		{"sunday to saturday", "sa 10:00-12:00", time.Date(2019, 3, 10, 10, 0, 0, 0, l), false, 22 * time.Hour},
+		{"past saturday", "sa 22:00-02:00", time.Date(2019, 3, 10, 1, 0, 0, 0, l), true, 3 * time.Hour},
+		{"last week", "mo 10:00-12:00", time.Date(2019, 3, 4, 8, 0, 0, 0, l), false, 164 * time.Hour},
+		{"spring forward", "sa 10:00-12:00", time.Date(2019, 3, 31, 10, 0, 0, 0, l), false, 21 * time.Hour},
+		{"fall back", "sa 10:00-12:00", time.Date(2019, 10, 27, 10, 0, 0, 0, l), false, 23 * time.Hour},
+		{"never", "su off", time.Date(2019, 10, 27, 10, 0, 0, 0, l), false, horizon * 24 * time.Hour},
+		{"always", "", time.Date(2019, 10, 27, 10, 0, 0, 0, l), true, horizon * 24 * time.Hour},
+		{"dated", "Apr-Oct 10:00-12:00", time.Date(2019, 11, 2, 10, 0, 0, 0, l), false, 46 * time.Hour},
+		{"dated never", "Jun off", time.Date(2019, 11, 2, 10, 0, 0, 0, l), false, horizon * 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, o := range []OpenHours{NewMust(tt.oh, l), NewMust(tt.oh, l).WithDSTPolicy(DSTSkip)} {
+				got, got1 := o.PrevDur(tt.args)
+				if got != tt.want {
+					t.Errorf("OpenHours.PrevDur() got = %v, want %v", got, tt.want)
+				}
+				if got1 != tt.want1 {
+					t.Errorf("OpenHours.PrevDur() got1 = %v, want %v", got1, tt.want1)
+				}
+			}
+		})
+	}
+}
+
+func TestOpenHours_PrevDate(t *testing.T) {
+	o, err := New("su 03:00-05:00", l)
+	if err != nil {
+		t.Error(err)
+	}
+	tests := []struct {
+		name  string
+		args  time.Time
+		want  bool
+		want1 time.Time
+	}{
+		{"closed 2 h ago", time.Date(2019, 3, 31, 7, 0, 0, 0, l), false, time.Date(2019, 3, 31, 5, 0, 0, 0, l)},
+		{"opened 1 h ago", time.Date(2019, 10, 27, 4, 0, 0, 0, l), true, time.Date(2019, 10, 27, 3, 0, 0, 0, l)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1 := o.PrevDate(tt.args)
+			if got != tt.want {
+				t.Errorf("OpenHours.PrevDate() got = %v, want %v", got, tt.want)
+			}
+			if !got1.Equal(tt.want1) {
+				t.Errorf("OpenHours.PrevDate() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+	}
+}
+
 func pDate(year int, month time.Month, day, hour, min, sec, nsec int, loc *time.Location) *time.Time {
 	t := time.Date(year, month, day, hour, min, sec, nsec, loc)
 	return &t
@@ -368,7 +436,7 @@ func TestOpenHours_InvalidPanic(t *testing.T) {
 		}
 	}()
 	_, err := New("mo 10:00", nil)
-	if err != ErrInvalidFormat {
+	if !errors.Is(err, ErrInvalidFormat) {
 		t.Error(err)
 	}
 }
@@ -421,3 +489,460 @@ func TestOpenHours_ClosingAfterMidnight(t *testing.T) {
 		})
 	}
 }
+
+func Test_simplifyDates(t *testing.T) {
+	tests := []struct {
+		args    string
+		want    []dateRange
+		wantErr bool
+	}{
+		{"", []dateRange{}, false},
+		{"jun", []dateRange{{6, 1, 6, 31}}, false},
+		{"jun-aug", []dateRange{{6, 1, 8, 31}}, false},
+		{"dec 25", []dateRange{{12, 25, 12, 25}}, false},
+		{"dec 24-26", []dateRange{{12, 24, 12, 26}}, false},
+		{"dec 24-jan 02", []dateRange{{12, 24, 1, 2}}, false},
+		{"dec 24-jan 02,jul", []dateRange{{12, 24, 1, 2}, {7, 1, 7, 31}}, false},
+		{"jun-26", nil, true},
+		{"dec 32", nil, true},
+		{"dec 24-jan-feb", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.args, func(t *testing.T) {
+			got, err := simplifyDates(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("simplifyDates() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("simplifyDates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_isDate(t *testing.T) {
+	tests := []struct {
+		str        string
+		afterMonth bool
+		want       bool
+	}{
+		{"dec", false, true},
+		{"apr-oct", false, true},
+		{"dec,jul", false, true},
+		{"24", true, true},
+		{"24", false, false},
+		{"10:00-12:00", true, false},
+		{"mardi", false, false},
+		{"mayday", false, false},
+		{"mo-fr", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.str, func(t *testing.T) {
+			if got := isDate(tt.str, tt.afterMonth); got != tt.want {
+				t.Errorf("isDate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if _, err := New("mardi 10:00-12:00", l); err != nil {
+		t.Errorf("New() error = %v", err)
+	}
+}
+
+func TestOpenHours_Months(t *testing.T) {
+	o := NewMust("Apr-Oct Mo-Su 10:00-22:00; Nov-Mar Mo-Fr 12:00-20:00", l)
+	xmas := NewMust("Mo-Sa 09:00-18:00; Dec 24-Jan 02 Mo-Sa 09:00-12:00", l)
+	tests := []struct {
+		name string
+		o    OpenHours
+		now  time.Time
+		want bool
+	}{
+		{"summer sunday", o, time.Date(2019, 7, 7, 11, 0, 0, 0, l), true},
+		{"summer late", o, time.Date(2019, 7, 8, 21, 0, 0, 0, l), true},
+		{"winter sunday", o, time.Date(2019, 12, 1, 13, 0, 0, 0, l), false},
+		{"winter late", o, time.Date(2019, 12, 2, 21, 0, 0, 0, l), false},
+		{"winter monday", o, time.Date(2019, 12, 2, 13, 0, 0, 0, l), true},
+		{"first of april", o, time.Date(2019, 4, 1, 10, 0, 0, 0, l), true},
+		{"last of march", o, time.Date(2019, 3, 31, 10, 0, 0, 0, l), false},
+		{"before christmas", xmas, time.Date(2019, 12, 23, 15, 0, 0, 0, l), true},
+		{"christmas eve", xmas, time.Date(2019, 12, 24, 15, 0, 0, 0, l), true},
+		{"new year", xmas, time.Date(2020, 1, 2, 11, 0, 0, 0, l), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.o.Match(tt.now); got != tt.want {
+				t.Errorf("OpenHours.Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenHours_Months_NextDate(t *testing.T) {
+	o := NewMust("Apr-Oct Mo-Su 10:00-22:00; Nov-Mar Mo-Fr 12:00-20:00", l)
+	summer := NewMust("Jun-Aug 10:00-18:00", l)
+	xmas := NewMust("Dec 25 10:00-12:00", l)
+	tests := []struct {
+		name  string
+		o     OpenHours
+		args  time.Time
+		want  bool
+		want1 time.Time
+	}{
+		{"end of october", o, time.Date(2019, 10, 31, 23, 0, 0, 0, l), false, time.Date(2019, 11, 1, 12, 0, 0, 0, l)},
+		{"end of march, clock change", o, time.Date(2019, 3, 29, 21, 0, 0, 0, l), false, time.Date(2019, 4, 1, 10, 0, 0, 0, l)},
+		{"open in april", o, time.Date(2019, 4, 6, 11, 0, 0, 0, l), true, time.Date(2019, 4, 6, 22, 0, 0, 0, l)},
+		{"before summer", summer, time.Date(2019, 5, 2, 11, 0, 0, 0, l), false, time.Date(2019, 6, 1, 10, 0, 0, 0, l)},
+		{"next year", xmas, time.Date(2019, 12, 25, 13, 0, 0, 0, l), false, time.Date(2020, 12, 25, 10, 0, 0, 0, l)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1 := tt.o.NextDate(tt.args)
+			if got != tt.want {
+				t.Errorf("OpenHours.NextDate() got = %v, want %v", got, tt.want)
+			}
+			if !got1.Equal(tt.want1) {
+				t.Errorf("OpenHours.NextDate() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+	}
+}
+
+func TestOpenHours_Months_When(t *testing.T) {
+	o := NewMust("Apr-Oct Mo-Su 10:00-22:00; Nov-Mar Mo-Fr 12:00-20:00", l)
+	tests := []struct {
+		name string
+		t    time.Time
+		d    time.Duration
+		want *time.Time
+	}{
+		{"fits now", time.Date(2019, 10, 31, 12, 0, 0, 0, l), time.Hour * 8, pDate(2019, 10, 31, 12, 0, 0, 0, l)},
+		{"too long for winter", time.Date(2019, 10, 31, 15, 0, 0, 0, l), time.Hour * 9, pDate(2020, 4, 1, 10, 0, 0, 0, l)},
+		{"winter weekend", time.Date(2019, 11, 2, 12, 0, 0, 0, l), time.Hour, pDate(2019, 11, 4, 12, 0, 0, 0, l)},
+		{"never", time.Date(2019, 11, 2, 12, 0, 0, 0, l), time.Hour * 13, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := o.When(tt.t, tt.d); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("OpenHours.When() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenHours_Sunday(t *testing.T) {
+	o := NewMust("su 10:00-12:00;mo 10:00-12:00,11:00-13:00", time.UTC)
+	tests := []struct {
+		now  time.Time
+		want bool
+	}{
+		{time.Date(2019, 3, 3, 11, 0, 0, 0, time.UTC), true},
+		{time.Date(2019, 3, 3, 13, 0, 0, 0, time.UTC), false},
+		{time.Date(2019, 3, 3, 23, 0, 0, 0, time.UTC), false},
+		{time.Date(2019, 3, 4, 11, 30, 0, 0, time.UTC), true},
+		{time.Date(2019, 3, 4, 12, 30, 0, 0, time.UTC), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.now.String(), func(t *testing.T) {
+			if got := o.Match(tt.now); got != tt.want {
+				t.Errorf("OpenHours.Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_subtract(t *testing.T) {
+	tests := []struct {
+		name string
+		o    []time.Time
+		off  []time.Time
+		want []time.Time
+	}{
+		{"middle", []time.Time{newDate(1, 8, 0, 0, 0, l), newDate(1, 18, 0, 0, 0, l)}, []time.Time{newDate(1, 12, 0, 0, 0, l), newDate(1, 14, 0, 0, 0, l)},
+			[]time.Time{newDate(1, 8, 0, 0, 0, l), newDate(1, 12, 0, 0, 0, l), newDate(1, 14, 0, 0, 0, l), newDate(1, 18, 0, 0, 0, l)}},
+		{"start", []time.Time{newDate(1, 8, 0, 0, 0, l), newDate(1, 18, 0, 0, 0, l)}, []time.Time{newDate(1, 7, 0, 0, 0, l), newDate(1, 9, 0, 0, 0, l)},
+			[]time.Time{newDate(1, 9, 0, 0, 0, l), newDate(1, 18, 0, 0, 0, l)}},
+		{"end", []time.Time{newDate(1, 8, 0, 0, 0, l), newDate(1, 18, 0, 0, 0, l)}, []time.Time{newDate(1, 17, 0, 0, 0, l), newDate(1, 19, 0, 0, 0, l)},
+			[]time.Time{newDate(1, 8, 0, 0, 0, l), newDate(1, 17, 0, 0, 0, l)}},
+		{"all", []time.Time{newDate(1, 8, 0, 0, 0, l), newDate(1, 18, 0, 0, 0, l)}, []time.Time{newDate(1, 0, 0, 0, 0, l), newDate(1, 24, 0, 0, 0, l)},
+			[]time.Time{}},
+		{"none", []time.Time{newDate(1, 8, 0, 0, 0, l), newDate(1, 18, 0, 0, 0, l)}, []time.Time{newDate(2, 8, 0, 0, 0, l), newDate(2, 18, 0, 0, 0, l)},
+			[]time.Time{newDate(1, 8, 0, 0, 0, l), newDate(1, 18, 0, 0, 0, l)}},
+		{"two holes", []time.Time{newDate(1, 8, 0, 0, 0, l), newDate(1, 18, 0, 0, 0, l)}, []time.Time{newDate(1, 15, 0, 0, 0, l), newDate(1, 16, 0, 0, 0, l), newDate(1, 10, 0, 0, 0, l), newDate(1, 11, 0, 0, 0, l)},
+			[]time.Time{newDate(1, 8, 0, 0, 0, l), newDate(1, 10, 0, 0, 0, l), newDate(1, 11, 0, 0, 0, l), newDate(1, 15, 0, 0, 0, l), newDate(1, 16, 0, 0, 0, l), newDate(1, 18, 0, 0, 0, l)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := subtract(tt.o, tt.off); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("subtract() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNew_Off(t *testing.T) {
+	tests := []struct {
+		name string
+		args string
+		want OpenHours
+	}{
+		{"hole", "Mo-Fr 08:00-18:00; We 12:00-14:00 off", NewMust("Mo,Tu,Th,Fr 08:00-18:00; We 08:00-12:00,14:00-18:00", l)},
+		{"closed", "Mo-Fr 08:00-18:00; We 12:00-14:00 closed", NewMust("Mo,Tu,Th,Fr 08:00-18:00; We 08:00-12:00,14:00-18:00", l)},
+		{"whole day", "Mo-Su 08:00-18:00; Su off", NewMust("Mo-Sa 08:00-18:00", l)},
+		{"order matters", "Su off; Mo-Su 08:00-18:00", NewMust("Mo-Su 08:00-18:00", l)},
+		{"after midnight", "Mo-Su 20:00-02:00; Fr 23:00-02:00 off", NewMust("Mo-Th,Sa,Su 20:00-02:00; Fr 20:00-23:00", l)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(tt.args, l)
+			if err != nil {
+				t.Error(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("New() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenHours_Off_Dated(t *testing.T) {
+	o := NewMust("Mo-Su 10:00-20:00; Dec 25 off; Dec 24 18:00-20:00 off; Jan 01 off; Jan 01 14:00-16:00", l)
+	tests := []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{"normal day", time.Date(2019, 12, 23, 19, 0, 0, 0, l), true},
+		{"christmas eve early", time.Date(2019, 12, 24, 17, 0, 0, 0, l), true},
+		{"christmas eve late", time.Date(2019, 12, 24, 19, 0, 0, 0, l), false},
+		{"christmas", time.Date(2019, 12, 25, 11, 0, 0, 0, l), false},
+		{"new year morning", time.Date(2020, 1, 1, 11, 0, 0, 0, l), false},
+		{"new year afternoon", time.Date(2020, 1, 1, 15, 0, 0, 0, l), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := o.Match(tt.now); got != tt.want {
+				t.Errorf("OpenHours.Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenHours_Off_Never(t *testing.T) {
+	now := time.Date(2019, 10, 27, 10, 0, 0, 0, l)
+	for _, str := range []string{"su off", "Mo-Su 10:00-20:00; Mo-Su off"} {
+		t.Run(str, func(t *testing.T) {
+			o := NewMust(str, l)
+			if isOpen, dur := o.NextDur(now); isOpen || dur != horizon*24*time.Hour {
+				t.Errorf("OpenHours.NextDur() = %v, %v, want false, %v", isOpen, dur, horizon*24*time.Hour)
+			}
+			if isOpen, date := o.NextDate(now); isOpen || !date.Equal(now.Add(horizon*24*time.Hour)) {
+				t.Errorf("OpenHours.NextDate() = %v, %v", isOpen, date)
+			}
+			if got := o.When(now, time.Hour); got != nil {
+				t.Errorf("OpenHours.When() = %v, want nil", got)
+			}
+		})
+	}
+}
+
+func TestOpenHours_Location(t *testing.T) {
+	weekly := NewMust("mo-fr 09:00-17:00", nil)
+	withEx, err := weekly.WithExceptions(Exceptions{{2030, 1, 1}: "off"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hours := map[string]OpenHours{"weekly": weekly, "holidays": NewMust("mo-fr 09:00-17:00; PH off", nil), "exceptions": withEx}
+	tests := []struct {
+		name string
+		now  time.Time
+		want bool
+		dur  time.Duration
+	}{
+		{"before opening in utc", time.Date(2019, 7, 1, 9, 30, 0, 0, l), false, 30 * time.Minute},
+		{"after opening in utc", time.Date(2019, 7, 1, 10, 30, 0, 0, l), true, 7*time.Hour + 30*time.Minute},
+	}
+	for name, o := range hours {
+		for _, tt := range tests {
+			t.Run(name+" "+tt.name, func(t *testing.T) {
+				if got := o.Match(tt.now); got != tt.want {
+					t.Errorf("OpenHours.Match() = %v, want %v", got, tt.want)
+				}
+				if isOpen, dur := o.NextDur(tt.now); isOpen != tt.want || dur != tt.dur {
+					t.Errorf("OpenHours.NextDur() = %v, %v, want %v, %v", isOpen, dur, tt.want, tt.dur)
+				}
+			})
+		}
+	}
+}
+
+func TestOpenHours_CrossZone(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := NewMust("mo-fr 09:00-17:00", ny)
+	tests := []struct {
+		name string
+		now  time.Time
+		want bool
+		dur  time.Duration
+		when time.Time
+	}{
+		{"morning in utc", time.Date(2019, 3, 4, 10, 0, 0, 0, time.UTC), false, 4 * time.Hour, time.Date(2019, 3, 4, 14, 0, 0, 0, time.UTC)},
+		{"evening in utc", time.Date(2019, 3, 4, 20, 0, 0, 0, time.UTC), true, 2 * time.Hour, time.Date(2019, 3, 4, 20, 0, 0, 0, time.UTC)},
+		{"tuesday in tokyo", time.Date(2019, 3, 5, 2, 0, 0, 0, time.FixedZone("JST", 9*3600)), true, 5 * time.Hour, time.Date(2019, 3, 5, 2, 0, 0, 0, time.FixedZone("JST", 9*3600))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := o.Match(tt.now); got != tt.want {
+				t.Errorf("OpenHours.Match() = %v, want %v", got, tt.want)
+			}
+			if isOpen, dur := o.NextDur(tt.now); isOpen != tt.want || dur != tt.dur {
+				t.Errorf("OpenHours.NextDur() = %v, %v, want %v, %v", isOpen, dur, tt.want, tt.dur)
+			}
+			if got := o.When(tt.now, time.Hour); got == nil || !got.Equal(tt.when) {
+				t.Errorf("OpenHours.When() = %v, want %v", got, tt.when)
+			}
+		})
+	}
+}
+
+// TestOpenHours_Weekly checks the search of the weekly windows against the windows read day by day,
+// which the exceptions of a far away date force
+func TestOpenHours_Weekly(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, str := range []string{"mo-fr 09:00-17:00", "sa 22:00-02:00", "fr-mo 20:00-10:00", "sa 20:00-26:00", "su 00:00-02:00; sa 22:00-24:00", "su 01:00-03:00; sa 22:00-02:00"} {
+		o := NewMust(str, l)
+		daily, err := o.WithExceptions(Exceptions{{2100, 1, 1}: "off"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for range 500 {
+			now := time.Date(2019, 1, 1, 0, 0, 0, 0, l).Add(time.Duration(r.Intn(365*24*60)) * time.Minute)
+			if got, want := o.Match(now), daily.Match(now); got != want {
+				t.Errorf("%v: OpenHours.Match(%v) = %v, want %v", str, now, got, want)
+			}
+			if got, got1 := o.NextDur(now); true {
+				if want, want1 := daily.NextDur(now); got != want || got1 != want1 {
+					t.Errorf("%v: OpenHours.NextDur(%v) = %v, %v, want %v, %v", str, now, got, got1, want, want1)
+				}
+			}
+			if got, got1 := o.PrevDur(now); true {
+				if want, want1 := daily.PrevDur(now); got != want || got1 != want1 {
+					t.Errorf("%v: OpenHours.PrevDur(%v) = %v, %v, want %v, %v", str, now, got, got1, want, want1)
+				}
+			}
+			if got, want := o.When(now, 90*time.Minute), daily.When(now, 90*time.Minute); got == nil || want == nil || !got.Equal(*want) {
+				t.Errorf("%v: OpenHours.When(%v) = %v, want %v", str, now, got, want)
+			}
+		}
+	}
+}
+
+// manyWindows returns open hours of 24 windows a day
+func manyWindows() OpenHours {
+	windows := []string{}
+	for h := 0; h < 24; h++ {
+		windows = append(windows, fmt.Sprintf("%02d:00-%02d:30", h, h))
+	}
+	return NewMust("mo-su "+strings.Join(windows, ","), l)
+}
+
+func TestOpenHours_Allocs(t *testing.T) {
+	o := manyWindows()
+	d := time.Date(2019, 3, 6, 10, 15, 0, 0, l)
+	if n := testing.AllocsPerRun(100, func() { o.Match(d) }); n != 0 {
+		t.Errorf("OpenHours.Match() allocs = %v, want 0", n)
+	}
+	if n := testing.AllocsPerRun(100, func() { o.NextDur(d) }); n != 0 {
+		t.Errorf("OpenHours.NextDur() allocs = %v, want 0", n)
+	}
+	dated := datedHours()
+	if n := testing.AllocsPerRun(100, func() { dated.Match(d) }); n != 0 {
+		t.Errorf("OpenHours.Match() dated allocs = %v, want 0", n)
+	}
+	if n := testing.AllocsPerRun(100, func() { dated.NextDur(d) }); n != 0 {
+		t.Errorf("OpenHours.NextDur() dated allocs = %v, want 0", n)
+	}
+}
+
+// datedHours returns open hours depending on the date, closed on the public holidays
+func datedHours() OpenHours {
+	return NewMust("Mo-Fr 09:00-17:00; PH off", l).WithPublicHolidays(PublicHolidays["GB"])
+}
+
+// TestOpenHours_Dated checks Match and NextDur against the windows built day by day
+func TestOpenHours_Dated(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	hours := []OpenHours{
+		datedHours(),
+		NewMust("Apr-Oct Mo-Su 10:00-22:00; Nov-Mar Mo-Fr 12:00-20:00", l),
+		NewMust("Mo-Su 20:00-02:00; Dec 24-Jan 02 off; Dec 31 22:00-04:00; PH 10:00-12:00", l).WithPublicHolidays(PublicHolidays["GB"]),
+		NewMust("Su 00:30-03:00; Sa 24:00-01:30; Jul off", l).WithDSTPolicy(DSTWallClock),
+		NewMust("Su 00:30-03:00; Sa 22:00-01:30", l).WithDSTPolicy(DSTElapsed),
+		NewMust("Su 01:30-03:00", l).WithDSTPolicy(DSTSkip),
+		// windows lasting for days
+		NewMust("", l).WithDSTPolicy(DSTSkip),
+		NewMust("", l).WithDSTPolicy(DSTWallClock),
+		NewMust("", l).WithDSTPolicy(DSTElapsed),
+		must(NewMust("Mo-Fr 00:00-24:00", l).WithExceptions(Exceptions{{2019, time.December, 25}: "off"})),
+		NewMust("Mo-Fr 00:00-24:00", l).WithDSTPolicy(DSTElapsed),
+		NewMust("Fr 18:00-24:00; Sa 00:00-24:00", l).WithDSTPolicy(DSTWallClock),
+		NewMust("Fr 18:00-24:00; Sa 00:00-24:00; Jul off", l),
+	}
+	for _, o := range hours {
+		for range 300 {
+			now := time.Date(2019, 1, 1, 0, 0, 0, 0, l).Add(time.Duration(r.Intn(2*365*24*4)) * 15 * time.Minute)
+			end := now.AddDate(0, 0, 60)
+			w := o.windows(now, end)
+			i := matchIndex(w, now)
+			if got := o.Match(now); got != (i%2 == 1) {
+				t.Errorf("%v: OpenHours.Match(%v) = %v, want %v", o.OSMString(), now, got, i%2 == 1)
+			}
+			isOpen, next := o.NextDate(now)
+			if i < len(w) && w[i].Before(end.AddDate(0, 0, -2)) { // the last window may go on after end
+				if isOpen != (i%2 == 1) || !next.Equal(w[i]) {
+					t.Errorf("%v: OpenHours.NextDate(%v) = %v, %v, want %v", o.OSMString(), now, isOpen, next, w[i])
+				}
+			} else if isOpen != (i%2 == 1) || next.Before(end.AddDate(0, 0, -2)) {
+				t.Errorf("%v: OpenHours.NextDate(%v) = %v, %v, want %v after %v", o.OSMString(), now, isOpen, next, i%2 == 1, end)
+			}
+		}
+	}
+}
+
+func BenchmarkOpenHours_Match(b *testing.B) {
+	o := manyWindows()
+	d := time.Date(2019, 3, 9, 23, 45, 0, 0, l)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		o.Match(d)
+	}
+}
+
+func BenchmarkOpenHours_NextDur(b *testing.B) {
+	o := manyWindows()
+	d := time.Date(2019, 3, 9, 23, 45, 0, 0, l)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		o.NextDur(d)
+	}
+}
+
+func BenchmarkOpenHours_Match_Dated(b *testing.B) {
+	o := datedHours()
+	d := time.Date(2019, 3, 9, 23, 45, 0, 0, l)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		o.Match(d)
+	}
+}
+
+func BenchmarkOpenHours_NextDur_Dated(b *testing.B) {
+	o := datedHours()
+	d := time.Date(2019, 3, 9, 23, 45, 0, 0, l)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		o.NextDur(d)
+	}
+}
@@ -0,0 +1,233 @@
+package openhours
+
+import (
+	"strings"
+	"time"
+)
+
+var osmDays = [7]string{"Su", "Mo", "Tu", "We", "Th", "Fr", "Sa"}
+
+// OSMString returns the open hours in the shortest opening_hours syntax of OpenStreetMap,
+// grouping the days with the same windows, so that New(o.OSMString(), loc) gives o back.
+// Open hours depending on the date are written rule by rule, without their exceptions.
+func (o OpenHours) OSMString() string {
+	if o.rules != nil {
+		return o.osmRules()
+	}
+	if len(o.week) == 0 {
+		return "off"
+	}
+	days := o.dayWindows()
+	groups := map[string][]int{}
+	keys := []string{}
+	for _, day := range []int{1, 2, 3, 4, 5, 6, 0} {
+		if len(days[day]) == 0 {
+			continue
+		}
+		key := strings.Join(days[day], ",")
+		if _, exist := groups[key]; !exist {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], day)
+	}
+	rules := []string{}
+	for _, key := range keys {
+		rules = append(rules, formatDays(groups[key])+" "+key)
+	}
+	return strings.Join(rules, "; ")
+}
+
+// dayWindows returns the windows of the weekly open hours as "10:00-12:00", by week day from sunday
+func (o OpenHours) dayWindows() [7][]string {
+	days := [7][]string{}
+	for day, pairs := range o.dayPairs() {
+		for _, p := range pairs {
+			days[day] = append(days[day], formatWindow(p[0], p[1]))
+		}
+	}
+	return days
+}
+
+// dayPairs returns the open and close dates of the weekly open hours cut at midnight, by week day from sunday.
+// A window going past midnight is kept whole when it closes the next day before its opening time.
+func (o OpenHours) dayPairs() [7][][2]time.Time {
+	days := [7][][2]time.Time{}
+	for i := 0; i < len(o.week)-1; i += 2 {
+		from, to := o.week[i], o.week[i+1]
+		for from.Before(to) {
+			day := weekDay(from)
+			midnight := newDate(day+1, 0, 0, 0, 0, from.Location())
+			if day == 7 { // what is left of a window going past saturday, "Sa 24:00-05:00"
+				days[6] = append(days[6], [2]time.Time{from, to})
+				break
+			}
+			end := to
+			crossing := to.After(midnight) && to.Before(midnight.AddDate(0, 0, 1)) && clock(to) < clock(from)
+			if to.After(midnight) && !crossing {
+				end = midnight
+			}
+			days[day] = append(days[day], [2]time.Time{from, end})
+			from = end
+		}
+	}
+	return days
+}
+
+// osmRules writes each rule of open hours depending on the date, a rule being split by the groups of days with the same windows
+func (o OpenHours) osmRules() string {
+	rules := []string{}
+	for _, r := range o.rules {
+		prefix := []string{}
+		if len(r.dates) > 0 {
+			dates := []string{}
+			for _, d := range r.dates {
+				dates = append(dates, d.String())
+			}
+			prefix = append(prefix, strings.Join(dates, ","))
+		}
+		days, order := [7][]string{}, []int{}
+		for i := 0; i < len(r.week)-1; i += 2 {
+			day := min(weekDay(r.week[i]), 6) // a window opening past saturday is written "Sa 24:00-05:00"
+			if len(days[day]) == 0 {
+				order = append(order, day)
+			}
+			days[day] = append(days[day], formatWindow(r.week[i], r.week[i+1]))
+		}
+		write := func(selector string, windows []string) {
+			parts := append([]string{}, prefix...)
+			if selector != "" {
+				parts = append(parts, selector)
+			}
+			if key := strings.Join(windows, ","); !r.off || key != "00:00-24:00" {
+				parts = append(parts, key)
+			}
+			if r.off {
+				parts = append(parts, "off")
+			}
+			rules = append(rules, strings.Join(parts, " "))
+		}
+		first := []string{}
+		if len(order) > 0 {
+			first = days[order[0]]
+		}
+		switch {
+		case r.ph && r.sh:
+			write("PH,SH", first)
+			continue
+		case r.ph:
+			write("PH", first)
+			continue
+		case r.sh:
+			write("SH", first)
+			continue
+		case len(order) == 0:
+			continue
+		}
+		groups := map[string][]int{}
+		keys := []string{}
+		for _, day := range []int{1, 2, 3, 4, 5, 6, 0} {
+			if len(days[day]) == 0 {
+				continue
+			}
+			key := strings.Join(days[day], ",")
+			if _, exist := groups[key]; !exist {
+				keys = append(keys, key)
+			}
+			groups[key] = append(groups[key], day)
+		}
+		for _, key := range keys {
+			selector := ""
+			if len(groups[key]) < 7 {
+				selector = formatDays(groups[key])
+			}
+			write(selector, strings.Split(key, ","))
+		}
+	}
+	if len(rules) == 0 { // no rule opens a day (eg: "May x off"), "" would be read as always open
+		return "off"
+	}
+	return strings.Join(rules, "; ")
+}
+
+func (r dateRange) String() string {
+	from := r.fromMonth.String()[:3]
+	switch {
+	case r.fromDay == 1 && r.toDay == 31 && r.fromMonth == r.toMonth:
+		return from
+	case r.fromDay == 1 && r.toDay == 31:
+		return from + "-" + r.toMonth.String()[:3]
+	}
+	from += " " + twoDigits(r.fromDay)
+	switch {
+	case r.fromMonth == r.toMonth && r.fromDay == r.toDay:
+		return from
+	case r.fromMonth == r.toMonth:
+		return from + "-" + twoDigits(r.toDay)
+	}
+	return from + "-" + r.toMonth.String()[:3] + " " + twoDigits(r.toDay)
+}
+
+func twoDigits(n int) string {
+	return string([]byte{byte('0' + n/10), byte('0' + n%10)})
+}
+
+// clock returns the time of the day of t
+func clock(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+}
+
+// formatTime returns "15:04", with the seconds if any
+func formatTime(t time.Time) string {
+	if t.Second() != 0 {
+		return t.Format("15:04:05")
+	}
+	return t.Format("15:04")
+}
+
+// formatWindow returns "10:00-12:00", closing at "24:00" at the end of the day
+func formatWindow(from, to time.Time) string {
+	start, end := formatTime(from), formatTime(to)
+	if weekDay(from) == 7 { // past saturday
+		start = "24:00"
+	}
+	if weekDay(to) > weekDay(from) && clock(to) == 0 {
+		end = "24:00"
+	}
+	return start + "-" + end
+}
+
+// formatDays returns the week days as "Mo-We,Fr", days being given from monday to sunday
+func formatDays(days []int) string {
+	in := [7]bool{}
+	for _, day := range days {
+		in[day] = true
+	}
+	if len(days) == 7 {
+		return "Mo-Su"
+	}
+	start := 1 // first day of the week with a day off before it, so that ranges can go over sunday
+	for in[(start+6)%7] {
+		start = (start + 1) % 7
+	}
+	parts := []string{}
+	for i := 0; i < 7; i++ {
+		day := (start + i) % 7
+		if !in[day] {
+			continue
+		}
+		n := 1
+		for i+n < 7 && in[(day+n)%7] {
+			n++
+		}
+		switch n {
+		case 1:
+			parts = append(parts, osmDays[day])
+		case 2:
+			parts = append(parts, osmDays[day]+","+osmDays[(day+1)%7])
+		default:
+			parts = append(parts, osmDays[day]+"-"+osmDays[(day+n-1)%7])
+		}
+		i += n - 1
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,101 @@
+package openhours
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOpenHours_OSMString(t *testing.T) {
+	tests := []struct {
+		args string
+		want string
+	}{
+		{"", "Mo-Su 00:00-24:00"},
+		{"mo 10:00-15:00", "Mo 10:00-15:00"},
+		{"mo-fr 09:00-17:00; sa 10:00-14:00", "Mo-Fr 09:00-17:00; Sa 10:00-14:00"},
+		{"sa 10:00-14:00; mo,tu,we,th,fr 09:00-17:00", "Mo-Fr 09:00-17:00; Sa 10:00-14:00"},
+		{"mo,we 10:00-12:00", "Mo,We 10:00-12:00"},
+		{"sa,su 10:00-12:00", "Sa,Su 10:00-12:00"},
+		{"fr-mo 10:00-12:00", "Fr-Mo 10:00-12:00"},
+		{"mo-fr 08:00-12:00,13:00-17:00; we 13:00-17:00 off", "Mo,Tu,Th,Fr 08:00-12:00,13:00-17:00; We 08:00-12:00"},
+		{"mo-su 22:00-02:00", "Mo-Su 22:00-02:00"},
+		{"mo 22:00-26:00", "Mo 22:00-02:00"},
+		{"mo 10:00-24:00; tu 00:00-02:00", "Mo 10:00-02:00"},
+		{"mo 10:00-24:00; tu 00:00-12:00", "Mo 10:00-24:00; Tu 00:00-12:00"},
+		{"mo 08:00:30-17:00", "Mo 08:00:30-17:00"},
+		{"su off", "off"},
+		{"fr 22:00-24:00; sa 00:00-24:00; sa 23:00-05:00", "Fr 22:00-24:00; Sa 00:00-24:00,24:00-05:00"},
+		{"Apr-Oct Mo-Su 10:00-22:00; Nov-Mar Mo-Fr 12:00-20:00", "Apr-Oct 10:00-22:00; Nov-Mar Mo-Fr 12:00-20:00"},
+		{"Dec 24-Jan 02 Mo-Sa 09:00-12:00; Dec 25,Dec 31 off; Jun 01-15 10:00-11:00", "Dec 24-Jan 02 Mo-Sa 09:00-12:00; Dec 25,Dec 31 off; Jun 01-15 10:00-11:00"},
+		{"Mo-Fr 09:00-18:00; Sa,PH 10:00-14:00; SH 12:00-13:00 off", "Mo-Fr 09:00-18:00; Sa 10:00-14:00; PH 10:00-14:00; SH 12:00-13:00 off"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.args, func(t *testing.T) {
+			o := NewMust(tt.args, l)
+			got := o.OSMString()
+			if got != tt.want {
+				t.Errorf("OpenHours.OSMString() = %v, want %v", got, tt.want)
+			}
+			if back := NewMust(got, l); !reflect.DeepEqual(back, o) {
+				t.Errorf("New(OpenHours.OSMString()) = %v, want %v", back, o)
+			}
+		})
+	}
+}
+
+func TestOpenHours_OSMString_NoDays(t *testing.T) {
+	o := NewMust("may x off", l)
+	got := o.OSMString()
+	if got != "off" {
+		t.Errorf("OpenHours.OSMString() = %q, want %q", got, "off")
+	}
+	if back := NewMust(got, l); back.Match(time.Date(2019, 5, 6, 10, 0, 0, 0, l)) {
+		t.Errorf("New(OpenHours.OSMString()) is open")
+	}
+}
+
+func TestOpenHours_OSMString_PastSaturday(t *testing.T) {
+	tests := []struct {
+		args string
+		want string
+	}{
+		{"Sa 24:00-02:00; PH off", "Sa 24:00-02:00; PH off"},
+		{"Jun Sa 24:00-02:00", "Jun Sa 24:00-02:00"},
+		{"Sa,Su 24:00-02:00; Jun off", "Mo 00:00-02:00; Sa 24:00-02:00; Jun off"},
+		{"Jun Sa 10:00-12:00,24:00-02:00", "Jun Sa 10:00-12:00,24:00-02:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.args, func(t *testing.T) {
+			o := NewMust(tt.args, l)
+			got := o.OSMString()
+			if got != tt.want {
+				t.Errorf("OpenHours.OSMString() = %q, want %q", got, tt.want)
+			}
+			if back := NewMust(got, l); !back.Equal(o) {
+				t.Errorf("New(OpenHours.OSMString()) = %v, want %v", back.OSMString(), got)
+			}
+		})
+	}
+}
+
+func Test_formatDays(t *testing.T) {
+	tests := []struct {
+		args []int
+		want string
+	}{
+		{[]int{1}, "Mo"},
+		{[]int{0, 1, 2, 3, 4, 5, 6}, "Mo-Su"},
+		{[]int{1, 2, 3, 5}, "Mo-We,Fr"},
+		{[]int{0, 6}, "Sa,Su"},
+		{[]int{0, 1, 5, 6}, "Fr-Mo"},
+		{[]int{0, 2, 4}, "Tu,Th,Su"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := formatDays(tt.args); got != tt.want {
+				t.Errorf("formatDays() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,352 @@
+package openhours
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Union returns the open hours open when o or other is open, in the location of o.
+// Open hours depending on the date can only be united with weekly open hours, which are added to each of their days,
+// else ErrDated is returned.
+func (o OpenHours) Union(other OpenHours) (OpenHours, error) {
+	switch {
+	case o.dated() && other.dated():
+		return OpenHours{}, ErrDated
+	case o.dated():
+		return o.withWeek(inLocation(other.week, o.location())), nil
+	case other.dated():
+		dated := other.WithLocation(o.location())
+		dated.dst = o.dst
+		return dated.withWeek(o.week), nil
+	}
+	return o.combine(other, func(a, b bool) bool { return a || b }), nil
+}
+
+// Intersect returns the open hours open when both o and other are open, in the location of o.
+// It returns ErrDated for open hours depending on the date.
+func (o OpenHours) Intersect(other OpenHours) (OpenHours, error) {
+	if o.dated() || other.dated() {
+		return OpenHours{}, ErrDated
+	}
+	return o.combine(other, func(a, b bool) bool { return a && b }), nil
+}
+
+// Subtract returns the open hours open when o is open and other is closed, in the location of o.
+// It returns ErrDated for open hours depending on the date.
+func (o OpenHours) Subtract(other OpenHours) (OpenHours, error) {
+	if o.dated() || other.dated() {
+		return OpenHours{}, ErrDated
+	}
+	return o.combine(other, func(a, b bool) bool { return a && !b }), nil
+}
+
+// Complement returns the open hours open when o is closed.
+// It returns ErrDated for open hours depending on the date.
+func (o OpenHours) Complement() (OpenHours, error) {
+	if o.dated() {
+		return OpenHours{}, ErrDated
+	}
+	return o.combine(OpenHours{}, func(a, _ bool) bool { return !a }), nil
+}
+
+// combine returns the open hours open when op is true, the weeks of o and other being read on the wall clock.
+// The windows going past saturday are cut at midnight and go on on sunday.
+func (o OpenHours) combine(other OpenHours, op func(a, b bool) bool) OpenHours {
+	loc := o.location()
+	a, b := fold(inLocation(o.week, loc), loc), fold(inLocation(other.week, loc), loc)
+	bounds := append([]time.Time{newDate(0, 0, 0, 0, 0, loc), newDate(7, 0, 0, 0, 0, loc)}, a...)
+	bounds = append(bounds, b...)
+	sort.Slice(bounds, func(i, j int) bool { return bounds[i].Before(bounds[j]) })
+	week := []time.Time{}
+	for i := 0; i < len(bounds)-1; i++ {
+		if bounds[i].Equal(bounds[i+1]) {
+			continue
+		}
+		if op(matchIndex(a, bounds[i])%2 == 1, matchIndex(b, bounds[i])%2 == 1) {
+			week = append(week, bounds[i], bounds[i+1])
+		}
+	}
+	o.week, o.rules, o.ex, o.loc = merge(week), nil, nil, loc
+	return o
+}
+
+// withWeek returns the open hours depending on the date o, open during the windows of the generic week too
+func (o OpenHours) withWeek(week []time.Time) OpenHours {
+	loc := o.location()
+	days := OpenHours{week: week}.dayPairs()
+	pairs := []time.Time{}
+	for _, day := range days {
+		for _, p := range day {
+			pairs = append(pairs, p[0], p[1])
+		}
+	}
+	if o.rules != nil {
+		o.rules = append(o.rules[:len(o.rules):len(o.rules)], rule{week: pairs})
+	} else {
+		o.week = merge(append(o.week[:len(o.week):len(o.week)], week...))
+	}
+	if o.ex == nil {
+		return o
+	}
+	ex := make(map[Date][]time.Duration, len(o.ex))
+	for date, durations := range o.ex {
+		weekday := int(time.Date(date.Year, date.Month, date.Day, 0, 0, 0, 0, time.UTC).Weekday())
+		midnight := newDate(weekday, 0, 0, 0, 0, loc)
+		w := []time.Time{}
+		for _, d := range durations {
+			w = append(w, midnight.Add(d))
+		}
+		for _, p := range days[weekday] {
+			w = append(w, p[0], p[1])
+		}
+		ex[date] = []time.Duration{}
+		for _, d := range merge(w) {
+			ex[date] = append(ex[date], d.Sub(midnight))
+		}
+	}
+	o.ex = ex
+	return o
+}
+
+// fold moves the part of the windows going past saturday to the start of the week
+func fold(week []time.Time, loc *time.Location) []time.Time {
+	end := newDate(7, 0, 0, 0, 0, loc)
+	folded := []time.Time{}
+	for i := 0; i < len(week)-1; i += 2 {
+		from, to := week[i], week[i+1]
+		switch {
+		case !from.Before(end):
+			folded = append(folded, from.AddDate(0, 0, -7), to.AddDate(0, 0, -7))
+		case to.After(end):
+			folded = append(folded, from, end, end.AddDate(0, 0, -7), to.AddDate(0, 0, -7))
+		default:
+			folded = append(folded, from, to)
+		}
+	}
+	return merge(folded)
+}
+
+// Equal returns true if o and other are open at the same times, read on the wall clock like Union,
+// with the same location (by name) and DST policy, as they change the real dates of the windows.
+// Open hours depending on the date are compared on each kind of day, see Contains.
+func (o OpenHours) Equal(other OpenHours) bool {
+	return o.dst == other.dst && o.location().String() == other.location().String() &&
+		!o.any(other, func(a, b bool) bool { return a != b })
+}
+
+// Contains returns true if o is open whenever other is open, read on the wall clock like Union, whatever their locations.
+// Open hours depending on the date are compared on each date of the year on each week day, on their exceptions,
+// and on the holidays as if any day could be one, the holidays of the same calendar being the same.
+func (o OpenHours) Contains(other OpenHours) bool {
+	return !o.any(other, func(a, b bool) bool { return b && !a })
+}
+
+// Overlaps returns true if o and other are open at the same time at least once, read on the wall clock like Contains
+func (o OpenHours) Overlaps(other OpenHours) bool {
+	return o.any(other, func(a, b bool) bool { return a && b })
+}
+
+// any returns true if op is true at some time, o and other being read on the wall clock
+func (o OpenHours) any(other OpenHours, op func(a, b bool) bool) bool {
+	if !o.dated() && !other.dated() {
+		return len(o.combine(other, op).week) > 0
+	}
+	os := [2]OpenHours{o.WithLocation(time.UTC), other.WithLocation(time.UTC)}
+	cals, uses := holidayCalendars(os)
+	for _, k := range dayKinds(os) {
+		start := time.Date(k.day.Year(), k.day.Month(), k.day.Day(), 0, 0, 0, 0, time.UTC)
+		end := start.AddDate(0, 0, 1)
+		days := [2]time.Time{k.day.AddDate(0, 0, -1), k.day} // the windows of the day before may go past midnight
+		// each holiday read by a rule may be a holiday or not
+		free := [][2]int{}
+		for c := range cals {
+			for j, d := range days {
+				if readsHoliday(os, k.ex, uses, c, d) {
+					free = append(free, [2]int{c, j})
+				}
+			}
+		}
+		for mask := 0; mask < 1<<len(free); mask++ {
+			holidays := make([][2]bool, len(cals))
+			for bit, f := range free {
+				holidays[f[0]][f[1]] = mask&(1<<bit) != 0
+			}
+			w := [2][]time.Time{}
+			for i, o := range os {
+				if !k.ex {
+					o.ex = nil
+				}
+				if c := uses[i][0]; c >= 0 {
+					o.ph = onDays(days, holidays[c])
+				}
+				if c := uses[i][1]; c >= 0 {
+					o.sh = onDays(days, holidays[c])
+				}
+				w[i] = merge(append(o.day(days[0]), o.day(days[1])...))
+			}
+			bounds := append([]time.Time{start, end}, w[0]...)
+			bounds = append(bounds, w[1]...)
+			sort.Slice(bounds, func(i, j int) bool { return bounds[i].Before(bounds[j]) })
+			for i := 0; i < len(bounds)-1; i++ {
+				if bounds[i].Before(start) || !bounds[i].Before(end) || bounds[i].Equal(bounds[i+1]) {
+					continue
+				}
+				if op(matchIndex(w[0], bounds[i])%2 == 1, matchIndex(w[1], bounds[i])%2 == 1) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// onDays returns the calendar with the holidays of the days
+func onDays(days [2]time.Time, holidays [2]bool) HolidayCalendar {
+	date := DateOf(days[1])
+	return HolidayFunc(func(t time.Time) bool {
+		if DateOf(t) == date {
+			return holidays[1]
+		}
+		return holidays[0]
+	})
+}
+
+// holidayCalendars returns the calendars read by the holiday rules of os, the same calendar once,
+// and the index of the public and school calendars of each open hours among them, -1 if they are not read
+func holidayCalendars(os [2]OpenHours) ([]HolidayCalendar, [2][2]int) {
+	cals := []HolidayCalendar{}
+	uses := [2][2]int{{-1, -1}, {-1, -1}}
+	for i, o := range os {
+		for j, cal := range [2]HolidayCalendar{o.ph, o.sh} {
+			read := false
+			for _, r := range o.rules {
+				read = read || j == 0 && r.ph || j == 1 && r.sh
+			}
+			if cal == nil || !read {
+				continue
+			}
+			uses[i][j] = len(cals)
+			for c, known := range cals {
+				if sameCalendar(cal, known) {
+					uses[i][j] = c
+				}
+			}
+			if uses[i][j] == len(cals) {
+				cals = append(cals, cal)
+			}
+		}
+	}
+	return cals, uses
+}
+
+// sameCalendar returns true if a and b are the same calendar, the functions, maps and slices being compared by address
+func sameCalendar(a, b HolidayCalendar) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.Type() != vb.Type() {
+		return false
+	}
+	switch va.Kind() {
+	case reflect.Func, reflect.Map, reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		return va.Pointer() == vb.Pointer()
+	case reflect.Slice:
+		return va.Pointer() == vb.Pointer() && va.Len() == vb.Len()
+	}
+	if va.Comparable() {
+		return va.Equal(vb)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// readsHoliday returns true if a holiday rule using the calendar c matches the day d
+func readsHoliday(os [2]OpenHours, ex bool, uses [2][2]int, c int, d time.Time) bool {
+	for i, o := range os {
+		if _, exist := o.ex[DateOf(d)]; ex && exist {
+			continue
+		}
+		for _, r := range o.rules {
+			if (r.ph && uses[i][0] == c || r.sh && uses[i][1] == c) && r.match(d.Month(), d.Day()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// dayKind is a day at noon in UTC, standing for the days matched by the same rules with the day before
+type dayKind struct {
+	day time.Time
+	ex  bool // the exceptions are read, else the day stands for the days without exceptions
+}
+
+// kindDay is a day at noon in UTC with the indexes of its date and of the date before in a leap year, see kindDays
+type kindDay struct {
+	t          time.Time
+	prev, date int
+}
+
+// kindDays returns a day for each date of the year on each week day, the years not changing the rules
+var kindDays = sync.OnceValue(func() []kindDay {
+	days := []kindDay{}
+	seen := map[[2]int]bool{}
+	for t := time.Date(2000, time.January, 1, 12, 0, 0, 0, time.UTC); t.Year() < 2028; t = t.AddDate(0, 0, 1) { // 28 years, a cycle of the calendar
+		key := [2]int{dateIndex(t), int(t.Weekday())}
+		if !seen[key] {
+			seen[key] = true
+			days = append(days, kindDay{t, dateIndex(t.AddDate(0, 0, -1)), key[0]})
+		}
+	}
+	return days
+})
+
+// dateIndex returns the index of the date of t in a leap year
+func dateIndex(t time.Time) int {
+	return time.Date(2000, t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).YearDay() - 1
+}
+
+// dayKinds returns a day of each kind of os: the days matched by the same rules with the day before, on the same week day,
+// and the exceptions with the day after them
+func dayKinds(os [2]OpenHours) []dayKind {
+	// the rules matching each date of a leap year, as an index of the different sets of rules
+	rules, ids, i := [366]int{}, map[string]int{}, 0
+	sig := []byte{}
+	for month := time.January; month <= time.December; month++ {
+		for day := 1; day <= time.Date(2000, month+1, 0, 0, 0, 0, 0, time.UTC).Day(); day++ {
+			sig = sig[:0]
+			for _, o := range os {
+				for _, r := range o.rules {
+					sig = append(sig, '0')
+					if r.match(month, day) {
+						sig[len(sig)-1] = '1'
+					}
+				}
+				sig = append(sig, '|')
+			}
+			id, exist := ids[string(sig)]
+			if !exist {
+				id = len(ids)
+				ids[string(sig)] = id
+			}
+			rules[i] = id
+			i++
+		}
+	}
+	kinds := []dayKind{}
+	n := len(ids)
+	seen := make([]bool, 7*n*n)
+	for _, d := range kindDays() {
+		key := (int(d.t.Weekday())*n+rules[d.prev])*n + rules[d.date]
+		if !seen[key] {
+			seen[key] = true
+			kinds = append(kinds, dayKind{d.t, false})
+		}
+	}
+	for _, o := range os {
+		for date := range o.ex {
+			t := time.Date(date.Year, date.Month, date.Day, 12, 0, 0, 0, time.UTC)
+			kinds = append(kinds, dayKind{t, true}, dayKind{t.AddDate(0, 0, 1), true})
+		}
+	}
+	return kinds
+}
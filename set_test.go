@@ -0,0 +1,195 @@
+package openhours
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOpenHours_Set(t *testing.T) {
+	tests := []struct {
+		name string
+		got  OpenHours
+		want string
+	}{
+		{"union", must(NewMust("mo-fr 09:00-12:00", l).Union(NewMust("mo-fr 11:00-17:00", l))), "mo-fr 09:00-17:00"},
+		{"union apart", must(NewMust("mo 09:00-12:00", l).Union(NewMust("tu 14:00-17:00", l))), "mo 09:00-12:00; tu 14:00-17:00"},
+		{"union past saturday", must(NewMust("sa 22:00-02:00", l).Union(NewMust("su 01:00-03:00", l))), "su 00:00-03:00; sa 22:00-24:00"},
+		{"intersect", must(NewMust("mo-su 11:00-23:00", l).Intersect(NewMust("mo-fr 08:00-20:00; sa,su 10:00-14:00", l))), "mo-fr 11:00-20:00; sa,su 11:00-14:00"},
+		{"intersect past saturday", must(NewMust("sa 22:00-02:00", l).Intersect(NewMust("su 00:00-01:00", l))), "su 00:00-01:00"},
+		{"intersect nothing", must(NewMust("mo 09:00-12:00", l).Intersect(NewMust("mo 12:00-17:00", l))), "su off"},
+		{"subtract", must(NewMust("mo-fr 09:00-17:00", l).Subtract(NewMust("we 12:00-14:00", l))), "mo-fr 09:00-17:00; we 12:00-14:00 off"},
+		{"subtract past saturday", must(NewMust("sa 22:00-02:00", l).Subtract(NewMust("su 01:00-12:00", l))), "su 00:00-01:00; sa 22:00-24:00"},
+		{"complement", must(NewMust("mo-fr 09:00-17:00", l).Complement()), "mo-fr 00:00-09:00,17:00-24:00; sa,su 00:00-24:00"},
+		{"complement always", must(NewMust("", l).Complement()), "su off"},
+		{"complement never", must(NewMust("su off", l).Complement()), ""},
+		{"wall clock", must(NewMust("mo 09:00-12:00", l).Union(NewMust("mo 11:00-17:00", time.UTC))), "mo 09:00-17:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if want := NewMust(tt.want, l); !reflect.DeepEqual(tt.got, want) {
+				t.Errorf("got %v, want %v", tt.got.String(), want.String())
+			}
+		})
+	}
+}
+
+// must returns o, failing on err
+func must(o OpenHours, err error) OpenHours {
+	if err != nil {
+		panic(err)
+	}
+	return o
+}
+
+func TestOpenHours_Set_Dated(t *testing.T) {
+	holiday := HolidayFunc(func(t time.Time) bool { return t.Month() == time.May && t.Day() == 6 }) // a monday
+	dated := NewMust("Mo-Fr 09:00-17:00; PH off", l).WithPublicHolidays(holiday)
+	tests := []struct {
+		name string
+		got  OpenHours
+		open []time.Time
+		shut []time.Time
+	}{
+		{
+			"dated and weekly",
+			must(dated.Union(NewMust("Sa 10:00-12:00; Mo 18:00-19:00", l))),
+			[]time.Time{time.Date(2019, 5, 4, 11, 0, 0, 0, l), time.Date(2019, 5, 6, 18, 30, 0, 0, l), time.Date(2019, 5, 7, 10, 0, 0, 0, l)},
+			[]time.Time{time.Date(2019, 5, 6, 10, 0, 0, 0, l), time.Date(2019, 5, 5, 11, 0, 0, 0, l)},
+		},
+		{
+			"weekly and dated",
+			must(NewMust("Sa 10:00-12:00", l).Union(dated)),
+			[]time.Time{time.Date(2019, 5, 4, 11, 0, 0, 0, l), time.Date(2019, 5, 7, 10, 0, 0, 0, l)},
+			[]time.Time{time.Date(2019, 5, 6, 10, 0, 0, 0, l)},
+		},
+		{
+			"exceptions",
+			must(must(NewMust("Mo-Fr 09:00-17:00", l).WithExceptions(Exceptions{{2019, time.May, 4}: "off", {2019, time.May, 6}: "off"})).Union(NewMust("Sa 10:00-12:00", l))),
+			[]time.Time{time.Date(2019, 5, 4, 11, 0, 0, 0, l), time.Date(2019, 5, 11, 11, 0, 0, 0, l), time.Date(2019, 5, 7, 10, 0, 0, 0, l)},
+			[]time.Time{time.Date(2019, 5, 6, 10, 0, 0, 0, l)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, at := range tt.open {
+				if !tt.got.Match(at) {
+					t.Errorf("%v.Match(%v) = false, want true", tt.got.String(), at)
+				}
+			}
+			for _, at := range tt.shut {
+				if tt.got.Match(at) {
+					t.Errorf("%v.Match(%v) = true, want false", tt.got.String(), at)
+				}
+			}
+		})
+	}
+	weekly := NewMust("Sa 10:00-12:00", l)
+	for name, f := range map[string]func() (OpenHours, error){
+		"union":      func() (OpenHours, error) { return dated.Union(dated) },
+		"intersect":  func() (OpenHours, error) { return dated.Intersect(weekly) },
+		"subtract":   func() (OpenHours, error) { return weekly.Subtract(dated) },
+		"complement": dated.Complement,
+	} {
+		if _, err := f(); err != ErrDated {
+			t.Errorf("%v error = %v, want %v", name, err, ErrDated)
+		}
+	}
+}
+
+func TestOpenHours_Equal_Dated(t *testing.T) {
+	holiday := HolidayFunc(func(t time.Time) bool { return DateOf(t) == Date{2019, time.May, 6} })
+	weekdays := NewMust("Mo-Fr 09:00-17:00; PH off", l).WithPublicHolidays(holiday)
+	if !NewMust("Mo-Fr 09:00-17:00", l).Contains(weekdays) {
+		t.Errorf("Mo-Fr 09:00-17:00 should contain %v", weekdays.OSMString())
+	}
+	if weekdays.Contains(NewMust("Mo-Fr 09:00-17:00", l)) {
+		t.Errorf("%v should not contain Mo-Fr 09:00-17:00", weekdays.OSMString())
+	}
+	if NewMust("Sa 09:00-17:00", l).Contains(weekdays) || NewMust("Sa 09:00-17:00", l).Overlaps(weekdays) {
+		t.Errorf("Sa 09:00-17:00 should not contain nor overlap %v", weekdays.OSMString())
+	}
+	if !weekdays.Equal(NewMust("Mo-Fr 09:00-17:00; PH off", l).WithPublicHolidays(holiday)) {
+		t.Errorf("%v should be equal to itself", weekdays.OSMString())
+	}
+	if weekdays.Equal(NewMust("Mo-Fr 09:00-17:00; PH off", l)) {
+		t.Errorf("%v should not be equal without holidays", weekdays.OSMString())
+	}
+	// the holidays are compared as if any day could be one
+	exceptions := must(NewMust("Mo-Fr 09:00-17:00", l).WithExceptions(Exceptions{{2019, time.May, 6}: "off"}))
+	if exceptions.Equal(weekdays) || exceptions.Contains(weekdays) || weekdays.Contains(exceptions) {
+		t.Errorf("%v and %v should not contain each other", exceptions.OSMString(), weekdays.OSMString())
+	}
+	gb := NewMust("Mo-Fr 09:00-17:00; PH off", l).WithPublicHolidays(PublicHolidays["GB"])
+	if !gb.Equal(NewMust("Mo-Fr 09:00-17:00; PH off", l).WithPublicHolidays(PublicHolidays["GB"])) {
+		t.Errorf("%v should be equal with the same calendar", gb.OSMString())
+	}
+	if gb.Equal(NewMust("Mo-Fr 09:00-17:00; PH off", l).WithPublicHolidays(PublicHolidays["FR"])) {
+		t.Errorf("%v should not be equal with another calendar", gb.OSMString())
+	}
+	if weekdays.Equal(weekdays.WithDSTPolicy(DSTSkip)) {
+		t.Errorf("%v should not be equal with another DST policy", weekdays.OSMString())
+	}
+	if NewMust("mo 09:00-17:00", l).Equal(NewMust("mo 09:00-17:00", l).WithDSTPolicy(DSTElapsed)) {
+		t.Errorf("mo 09:00-17:00 should not be equal with another DST policy")
+	}
+}
+
+func TestOpenHours_Equal(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the same wall clock times in another location are contained, but not equal as the real dates differ
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		a, b     string
+		locB     *time.Location
+		equal    bool
+		contains bool
+		overlaps bool
+	}{
+		{"mo-fr 09:00-17:00", "mo-fr 09:00-17:00", london, true, true, true},
+		{"mo-fr 09:00-17:00", "mo,tu,we,th,fr 09:00-12:00,12:00-17:00", l, true, true, true},
+		{"sa 22:00-02:00", "su 00:00-02:00; sa 22:00-24:00", l, true, true, true},
+		{"mo-fr 09:00-17:00", "mo 10:00-12:00", l, false, true, true},
+		{"mo 10:00-12:00", "mo-fr 09:00-17:00", l, false, false, true},
+		{"mo 10:00-12:00", "mo 12:00-14:00", l, false, false, false},
+		{"sa 22:00-02:00", "su 01:00-03:00", l, false, false, true},
+		{"su off", "mo 10:00-12:00 off", l, true, true, false},
+		{"Apr-Oct 10:00-12:00", "apr-oct 10:00-12:00", l, true, true, true},
+		{"Apr-Oct 10:00-12:00", "mo-su 10:00-12:00", l, false, false, true},
+		{"mo-su 10:00-12:00", "Apr-Oct 10:00-12:00", l, false, true, true},
+		{"Apr-Oct 10:00-12:00", "Nov-Mar 10:00-12:00", l, false, false, false},
+		{"Apr-Oct 10:00-12:00", "Apr-Oct 10:00-11:00,11:00-12:00", l, true, true, true},
+		{"Apr-Oct 10:00-12:00", "Apr-Jun 10:00-12:00; Jul-Oct 10:00-12:00", l, true, true, true},
+		{"Oct 31 22:00-02:00", "Oct 31 22:00-24:00; Nov 01 00:00-02:00", l, true, true, true},
+		{"mo-fr 09:00-17:00", "mo-fr 09:00-17:00", ny, false, true, true},
+		{"Apr-Oct 10:00-12:00", "Apr-Oct 10:00-12:00", ny, false, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" and "+tt.b, func(t *testing.T) {
+			a, b := NewMust(tt.a, l), NewMust(tt.b, tt.locB)
+			if got := a.Equal(b); got != tt.equal {
+				t.Errorf("OpenHours.Equal() = %v, want %v", got, tt.equal)
+			}
+			if got := a.Contains(b); got != tt.contains {
+				t.Errorf("OpenHours.Contains() = %v, want %v", got, tt.contains)
+			}
+			if got := a.Overlaps(b); got != tt.overlaps {
+				t.Errorf("OpenHours.Overlaps() = %v, want %v", got, tt.overlaps)
+			}
+		})
+	}
+}
+
+func BenchmarkOpenHours_Equal_Dated(b *testing.B) {
+	o, other := datedHours(), datedHours()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		o.Equal(other)
+	}
+}
@@ -0,0 +1,85 @@
+package openhours
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+)
+
+// Value returns the opening hours string of o, to store it in a text column.
+// It returns ErrExceptions if o has exceptions, which can be stored with MarshalJSON instead.
+func (o OpenHours) Value() (driver.Value, error) {
+	if o.ex != nil {
+		return nil, ErrExceptions
+	}
+	return o.OSMString(), nil
+}
+
+// Scan parses a text column with New, in the location of o, UTC by default.
+// Use WithLocation once the row is scanned if the location is stored in another column.
+func (o *OpenHours) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case string:
+		return o.parse(src, o.loc)
+	case []byte:
+		return o.parse(string(src), o.loc)
+	}
+	return fmt.Errorf("cannot scan %T into OpenHours", src)
+}
+
+// NullOpenHours is an OpenHours which may be null, like sql.NullString
+type NullOpenHours struct {
+	OpenHours OpenHours
+	Valid     bool // Valid is true if OpenHours is not NULL
+}
+
+// Value returns the opening hours string, or nil if n is not valid
+func (n NullOpenHours) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.OpenHours.Value()
+}
+
+// Scan parses a text column like OpenHours.Scan, NULL setting Valid to false
+func (n *NullOpenHours) Scan(src interface{}) error {
+	if src == nil {
+		n.OpenHours, n.Valid = OpenHours{loc: n.OpenHours.loc}, false
+		return nil
+	}
+	if err := n.OpenHours.Scan(src); err != nil {
+		n.Valid = false
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// WithLocation returns the open hours with the same wall clock times in loc, UTC if loc is nil
+func (o OpenHours) WithLocation(loc *time.Location) OpenHours {
+	if loc == nil {
+		loc = time.UTC
+	}
+	o.loc = loc
+	if o.week != nil {
+		o.week = inLocation(o.week, loc)
+	}
+	if o.rules != nil {
+		rules := make([]rule, len(o.rules))
+		for i, r := range o.rules {
+			r.week = inLocation(r.week, loc)
+			rules[i] = r
+		}
+		o.rules = rules
+	}
+	return o
+}
+
+// inLocation returns the dates with the same wall clock times in loc
+func inLocation(dates []time.Time, loc *time.Location) []time.Time {
+	res := make([]time.Time, len(dates))
+	for i, d := range dates {
+		res[i] = time.Date(d.Year(), d.Month(), d.Day(), d.Hour(), d.Minute(), d.Second(), d.Nanosecond(), loc)
+	}
+	return res
+}
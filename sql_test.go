@@ -0,0 +1,78 @@
+package openhours
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOpenHours_Scan(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     interface{}
+		loc     *time.Location
+		want    OpenHours
+		wantErr bool
+	}{
+		{"string", "mo-fr 09:00-17:00", nil, NewMust("mo-fr 09:00-17:00", time.UTC), false},
+		{"bytes", []byte("mo-fr 09:00-17:00"), l, NewMust("mo-fr 09:00-17:00", l), false},
+		{"invalid", "mo 09:00", nil, OpenHours{}, true},
+		{"null", nil, nil, OpenHours{}, true},
+		{"int", 42, nil, OpenHours{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := OpenHours{loc: tt.loc}
+			err := o.Scan(tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("OpenHours.Scan() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(o, tt.want) {
+				t.Errorf("OpenHours.Scan() = %v, want %v", o, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenHours_Value(t *testing.T) {
+	o := NewMust("mo-fr 09:00-17:00", l)
+	if v, err := o.Value(); err != nil || v != "Mo-Fr 09:00-17:00" {
+		t.Errorf("OpenHours.Value() = %v, %v", v, err)
+	}
+	o = must(o.WithExceptions(Exceptions{{2019, time.December, 25}: "off"}))
+	if v, err := o.Value(); err != ErrExceptions {
+		t.Errorf("OpenHours.Value() = %v, %v, want %v", v, err, ErrExceptions)
+	}
+}
+
+func TestNullOpenHours(t *testing.T) {
+	n := NullOpenHours{}
+	if err := n.Scan(nil); err != nil || n.Valid {
+		t.Errorf("NullOpenHours.Scan(nil) = %v, %v", n, err)
+	}
+	if v, err := n.Value(); v != nil || err != nil {
+		t.Errorf("NullOpenHours.Value() = %v, %v, want nil", v, err)
+	}
+	if err := n.Scan("sa,su 10:00-12:00"); err != nil || !n.Valid {
+		t.Errorf("NullOpenHours.Scan() = %v, %v", n, err)
+	}
+	if v, err := n.Value(); v != driver.Value("Sa,Su 10:00-12:00") || err != nil {
+		t.Errorf("NullOpenHours.Value() = %v, %v, want Sa,Su 10:00-12:00", v, err)
+	}
+}
+
+func TestOpenHours_WithLocation(t *testing.T) {
+	tests := []string{
+		"mo-fr 09:00-17:00; sa 22:00-02:00",
+		"Apr-Oct 10:00-22:00; Nov-Mar Mo-Fr 12:00-20:00; PH off",
+	}
+	for _, str := range tests {
+		t.Run(str, func(t *testing.T) {
+			got := NewMust(str, time.UTC).WithLocation(l)
+			if want := NewMust(str, l); !reflect.DeepEqual(got, want) {
+				t.Errorf("OpenHours.WithLocation() = %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package openhours
+
+import (
+	"iter"
+	"sort"
+	"time"
+)
+
+// Version is an open hours in effect from a date until the next version
+type Version struct {
+	From  time.Time
+	Hours OpenHours
+}
+
+// Timeline is a list of open hours versions (eg: "from 1 November our hours become ..."), closed before the first one
+type Timeline struct {
+	versions []Version // sorted by From
+}
+
+// NewTimeline returns a timeline of the versions, in any order.
+// When two versions start at the same date, the last one given is used.
+func NewTimeline(versions ...Version) Timeline {
+	tl := Timeline{}
+	for _, v := range versions {
+		tl = tl.Add(v.From, v.Hours)
+	}
+	return tl
+}
+
+// Add returns the timeline with o in effect from the date from
+func (tl Timeline) Add(from time.Time, o OpenHours) Timeline {
+	i := sort.Search(len(tl.versions), func(i int) bool { return !tl.versions[i].From.Before(from) })
+	versions := make([]Version, 0, len(tl.versions)+1)
+	versions = append(versions, tl.versions[:i]...)
+	versions = append(versions, Version{from, o})
+	if i < len(tl.versions) && tl.versions[i].From.Equal(from) {
+		i++
+	}
+	tl.versions = append(versions, tl.versions[i:]...)
+	return tl
+}
+
+// Versions returns the versions of the timeline, sorted by date
+func (tl Timeline) Versions() []Version {
+	return append([]Version{}, tl.versions...)
+}
+
+// index returns the index of the version in effect at t, -1 before the first one
+func (tl Timeline) index(t time.Time) int {
+	return sort.Search(len(tl.versions), func(i int) bool { return tl.versions[i].From.After(t) }) - 1
+}
+
+// At returns the open hours in effect at t, false before the first version
+func (tl Timeline) At(t time.Time) (OpenHours, bool) {
+	i := tl.index(t)
+	if i < 0 {
+		return OpenHours{}, false
+	}
+	return tl.versions[i].Hours, true
+}
+
+// Match returns true if the time t is in the open hours of the version in effect at t
+func (tl Timeline) Match(t time.Time) bool {
+	o, ok := tl.At(t)
+	return ok && o.Match(t)
+}
+
+// NextDate returns true if t is open and the date of the next change, going on with the next versions
+// when the open hours stay the same over the switch (eg: closed on the evening before and the morning after)
+func (tl Timeline) NextDate(t time.Time) (bool, time.Time) {
+	isOpen := tl.Match(t)
+	for i := tl.index(t); ; i++ {
+		if i+1 >= len(tl.versions) {
+			if i < 0 { // no version at all
+				return false, t.AddDate(0, 0, horizon)
+			}
+			_, next := tl.versions[i].Hours.NextDate(t)
+			return isOpen, next
+		}
+		from := tl.versions[i+1].From
+		if i >= 0 {
+			if _, next := tl.versions[i].Hours.NextDate(t); next.Before(from) {
+				return isOpen, next
+			}
+		}
+		t = from.In(t.Location())
+		if tl.versions[i+1].Hours.Match(t) != isOpen {
+			return isOpen, t
+		}
+	}
+}
+
+// NextDur returns true if t is open and the duration until the next change, see NextDate
+func (tl Timeline) NextDur(t time.Time) (bool, time.Duration) {
+	isOpen, next := tl.NextDate(t)
+	return isOpen, next.Sub(t)
+}
+
+// Intervals returns the open windows between from and to of the versions in effect, see OpenHours.Intervals
+func (tl Timeline) Intervals(from, to time.Time) []Interval {
+	intervals := []Interval{}
+	for i := range tl.All(from, to) {
+		intervals = append(intervals, i)
+	}
+	return intervals
+}
+
+// All returns the open windows between from and to like Intervals, joining the windows going on over a switch
+func (tl Timeline) All(from, to time.Time) iter.Seq[Interval] {
+	return func(yield func(Interval) bool) {
+		loc := from.Location()
+		current, pending := Interval{}, false
+		for i := max(tl.index(from), 0); i < len(tl.versions); i++ {
+			start, end := clip(tl.versions[i].From, to, from, to)
+			if i+1 < len(tl.versions) {
+				_, end = clip(start, tl.versions[i+1].From, from, to)
+			}
+			if !start.Before(end) {
+				if start.Before(to) {
+					continue
+				}
+				break
+			}
+			for w := range tl.versions[i].Hours.All(start.In(loc), end.In(loc)) {
+				if pending && current.End.Equal(w.Start) {
+					current.End = w.End
+					continue
+				}
+				if pending && !yield(current) {
+					return
+				}
+				current, pending = w, true
+			}
+		}
+		if pending {
+			yield(current)
+		}
+	}
+}
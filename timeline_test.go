@@ -0,0 +1,77 @@
+package openhours
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTimeline(t *testing.T) {
+	tl := NewTimeline(
+		Version{time.Date(2019, 11, 1, 0, 0, 0, 0, l), NewMust("mo-sa 10:00-20:00", l)},
+		Version{time.Date(2019, 10, 1, 0, 0, 0, 0, l), NewMust("mo-fr 09:00-17:00", l)},
+	)
+	matches := []struct {
+		args time.Time
+		want bool
+	}{
+		{time.Date(2019, 9, 30, 10, 0, 0, 0, l), false},
+		{time.Date(2019, 10, 31, 9, 30, 0, 0, l), true},
+		{time.Date(2019, 10, 31, 18, 0, 0, 0, l), false},
+		{time.Date(2019, 11, 1, 9, 30, 0, 0, l), false},
+		{time.Date(2019, 11, 1, 18, 0, 0, 0, l), true},
+		{time.Date(2019, 11, 2, 11, 0, 0, 0, l), true},
+	}
+	for _, tt := range matches {
+		if got := tl.Match(tt.args); got != tt.want {
+			t.Errorf("Timeline.Match(%v) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+	nexts := []struct {
+		args  time.Time
+		want  bool
+		want1 time.Time
+	}{
+		{time.Date(2019, 9, 28, 10, 0, 0, 0, l), false, time.Date(2019, 10, 1, 9, 0, 0, 0, l)},
+		{time.Date(2019, 10, 31, 18, 0, 0, 0, l), false, time.Date(2019, 11, 1, 10, 0, 0, 0, l)},
+		{time.Date(2019, 11, 1, 18, 0, 0, 0, l), true, time.Date(2019, 11, 1, 20, 0, 0, 0, l)},
+	}
+	for _, tt := range nexts {
+		got, got1 := tl.NextDate(tt.args)
+		if got != tt.want || !got1.Equal(tt.want1) {
+			t.Errorf("Timeline.NextDate(%v) = %v, %v, want %v, %v", tt.args, got, got1, tt.want, tt.want1)
+		}
+	}
+	gotIntervals := tl.Intervals(time.Date(2019, 10, 31, 0, 0, 0, 0, l), time.Date(2019, 11, 2, 12, 0, 0, 0, l))
+	wantIntervals := []Interval{
+		{time.Date(2019, 10, 31, 9, 0, 0, 0, l), time.Date(2019, 10, 31, 17, 0, 0, 0, l)},
+		{time.Date(2019, 11, 1, 10, 0, 0, 0, l), time.Date(2019, 11, 1, 20, 0, 0, 0, l)},
+		{time.Date(2019, 11, 2, 10, 0, 0, 0, l), time.Date(2019, 11, 2, 12, 0, 0, 0, l)},
+	}
+	if !reflect.DeepEqual(gotIntervals, wantIntervals) {
+		t.Errorf("Timeline.Intervals() = %v, want %v", gotIntervals, wantIntervals)
+	}
+}
+
+func TestTimeline_Switch(t *testing.T) {
+	switchover := time.Date(2019, 11, 1, 12, 0, 0, 0, l)
+	tl := NewTimeline(
+		Version{time.Date(2019, 10, 1, 0, 0, 0, 0, l), NewMust("mo-fr 09:00-17:00", l)},
+		Version{switchover, NewMust("mo-fr 10:00-18:00", l)},
+	)
+	if got, got1 := tl.NextDate(time.Date(2019, 11, 1, 9, 30, 0, 0, l)); !got || !got1.Equal(time.Date(2019, 11, 1, 18, 0, 0, 0, l)) {
+		t.Errorf("Timeline.NextDate() = %v, %v, want true, %v", got, got1, time.Date(2019, 11, 1, 18, 0, 0, 0, l))
+	}
+	got := tl.Intervals(time.Date(2019, 11, 1, 0, 0, 0, 0, l), time.Date(2019, 11, 2, 0, 0, 0, 0, l))
+	want := []Interval{{time.Date(2019, 11, 1, 9, 0, 0, 0, l), time.Date(2019, 11, 1, 18, 0, 0, 0, l)}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Timeline.Intervals() = %v, want %v", got, want)
+	}
+	replaced := tl.Add(switchover, NewMust("mo-fr 09:00-12:00", l))
+	if n := len(replaced.Versions()); n != 2 {
+		t.Errorf("len(Timeline.Versions()) = %v, want 2", n)
+	}
+	if got, got1 := replaced.NextDate(time.Date(2019, 11, 1, 9, 30, 0, 0, l)); !got || !got1.Equal(switchover) {
+		t.Errorf("Timeline.NextDate() = %v, %v, want true, %v", got, got1, switchover)
+	}
+}
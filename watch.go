@@ -0,0 +1,83 @@
+package openhours
+
+import (
+	"context"
+	"time"
+)
+
+// Clock gives the time to Watch, see SystemClock
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// SystemClock is the Clock of the time package
+var SystemClock Clock = systemClock{}
+
+// Transition is a change of the open hours, opening if Open is true else closing
+type Transition struct {
+	Open bool
+	At   time.Time
+}
+
+// Watch sends the state of o now then each opening and closing, at the dates given by NextDate read in the location of o,
+// until ctx is done. The next change is looked for again after each one, so that the changes of offset are followed.
+// If clock is nil, SystemClock is used.
+func Watch(ctx context.Context, o OpenHours, clock Clock) <-chan Transition {
+	if clock == nil {
+		clock = SystemClock
+	}
+	ch := make(chan Transition)
+	go func() {
+		defer close(ch)
+		now := clock.Now().In(o.location())
+		isOpen, next := o.NextDate(now)
+		if !send(ctx, ch, Transition{isOpen, now}) {
+			return
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-clock.After(next.Sub(now)):
+			}
+			now = clock.Now().In(o.location())
+			if now.Before(next) { // woken too early, wait again
+				continue
+			}
+			open, after := o.NextDate(now)
+			if open != isOpen {
+				at := next
+				if now.After(next) { // woken late (eg: after a sleep), the change may be a later one
+					_, at = o.PrevDate(now)
+				}
+				if !send(ctx, ch, Transition{open, at}) {
+					return
+				}
+				isOpen = open
+			}
+			next = after
+		}
+	}()
+	return ch
+}
+
+// send sends t on ch, returning false if ctx is done first
+func send(ctx context.Context, ch chan<- Transition, t Transition) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case ch <- t:
+		return true
+	}
+}
@@ -0,0 +1,110 @@
+package openhours
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock whose time only goes forward with advance
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+	armed   chan struct{}
+}
+
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now, armed: make(chan struct{}, 16)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	c.waiters = append(c.waiters, fakeWaiter{c.now.Add(d), ch})
+	c.armed <- struct{}{}
+	return ch
+}
+
+// advance waits for a waiter and moves the time to the first one, waking it
+func (c *fakeClock) advance() time.Time {
+	<-c.armed
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	first := 0
+	for i, w := range c.waiters {
+		if w.at.Before(c.waiters[first].at) {
+			first = i
+		}
+	}
+	w := c.waiters[first]
+	c.waiters = append(c.waiters[:first], c.waiters[first+1:]...)
+	c.now = w.at
+	w.ch <- w.at
+	return w.at
+}
+
+func TestWatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		oh    string
+		start time.Time
+		want  []Transition
+	}{
+		{"week", "mo-fr 09:00-17:00", time.Date(2019, 3, 7, 8, 0, 0, 0, l), []Transition{
+			{false, time.Date(2019, 3, 7, 8, 0, 0, 0, l)},
+			{true, time.Date(2019, 3, 7, 9, 0, 0, 0, l)},
+			{false, time.Date(2019, 3, 7, 17, 0, 0, 0, l)},
+			{true, time.Date(2019, 3, 8, 9, 0, 0, 0, l)},
+			{false, time.Date(2019, 3, 8, 17, 0, 0, 0, l)},
+			{true, time.Date(2019, 3, 11, 9, 0, 0, 0, l)},
+		}},
+		{"spring forward", "su 00:30-04:00", time.Date(2019, 3, 30, 12, 0, 0, 0, l), []Transition{
+			{false, time.Date(2019, 3, 30, 12, 0, 0, 0, l)},
+			{true, time.Date(2019, 3, 31, 0, 30, 0, 0, l)},
+			{false, time.Date(2019, 3, 31, 4, 0, 0, 0, l)},
+		}},
+		{"fall back", "su 00:30-04:00", time.Date(2019, 10, 26, 12, 0, 0, 0, l), []Transition{
+			{false, time.Date(2019, 10, 26, 12, 0, 0, 0, l)},
+			{true, time.Date(2019, 10, 27, 0, 30, 0, 0, l)},
+			{false, time.Date(2019, 10, 27, 4, 0, 0, 0, l)},
+		}},
+		{"never", "su off", time.Date(2019, 3, 7, 8, 0, 0, 0, l), []Transition{
+			{false, time.Date(2019, 3, 7, 8, 0, 0, 0, l)},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			clock := newFakeClock(tt.start)
+			ch := Watch(ctx, NewMust(tt.oh, l), clock)
+			for i, want := range tt.want {
+				if i > 0 {
+					clock.advance()
+				}
+				got := <-ch
+				if got.Open != want.Open || !got.At.Equal(want.At) {
+					t.Fatalf("Watch() sent %v, want %v", got, want)
+				}
+			}
+			cancel()
+			if _, ok := <-ch; ok {
+				t.Errorf("Watch() did not close the channel")
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package openhours
+
+import "time"
+
+// In returns the weekly open hours of o read on the wall clock of loc, with the time offsets in effect at the date at
+// (eg: the hours of a London warehouse for a team in New York). The windows crossing midnight in loc are split over the days.
+// Open hours depending on the date are read on the week of at, see InPeriod for the offsets changing over time.
+func (o OpenHours) In(loc *time.Location, at time.Time) OpenHours {
+	if loc == nil {
+		loc = time.UTC
+	}
+	_, before := at.In(o.location()).Zone()
+	_, after := at.In(loc).Zone()
+	shift := time.Duration(after-before) * time.Second
+	start := newDate(0, 0, 0, 0, 0, time.UTC)
+	week := []time.Time{}
+	w := o.weekAt(at)
+	for i := 0; i < len(w)-1; i += 2 {
+		from, to := w[i].Add(shift), w[i+1].Add(shift)
+		if from.Before(start) { // the window starts on the saturday before, fold moves what goes past saturday back
+			from, to = from.AddDate(0, 0, 7), to.AddDate(0, 0, 7)
+		}
+		week = append(week, from, to)
+	}
+	o.week, o.rules, o.ex, o.loc = inLocation(fold(merge(week), time.UTC), loc), nil, nil, loc
+	return o
+}
+
+// InPeriod returns the open hours of o read on the wall clock of loc from the date from, with a new version
+// at each change of time offset of o or loc before to (and each week for open hours depending on the date).
+// The last version goes on after to.
+func (o OpenHours) InPeriod(loc *time.Location, from, to time.Time) Timeline {
+	if loc == nil {
+		loc = time.UTC
+	}
+	changes := []time.Time{from}
+	for _, z := range []*time.Location{o.location(), loc} {
+		for t := from.In(z); ; {
+			_, end := t.ZoneBounds()
+			if end.IsZero() || !end.Before(to) {
+				break
+			}
+			changes = append(changes, end)
+			t = end
+		}
+	}
+	if o.dated() {
+		for d := sunday(from.In(o.location())).AddDate(0, 0, 7); d.Before(to); d = d.AddDate(0, 0, 7) {
+			changes = append(changes, d)
+		}
+	}
+	tl := Timeline{}
+	for _, c := range changes {
+		tl = tl.Add(c.In(loc), o.In(loc, c))
+	}
+	return tl
+}
+
+// weekAt returns the windows of o as a generic week in UTC, the one of at for open hours depending on the date
+func (o OpenHours) weekAt(at time.Time) []time.Time {
+	if !o.dated() {
+		return inLocation(o.week, time.UTC)
+	}
+	start := sunday(at.In(o.location()))
+	end := start.AddDate(0, 0, 7)
+	first := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	week := []time.Time{}
+	w := o.windows(start, end)
+	for i := 1; i < len(w); i += 2 {
+		s, e := clip(w[i-1], w[i], start, end)
+		if !s.Before(e) {
+			continue
+		}
+		for _, d := range []time.Time{s, e} {
+			days := int(time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC).Sub(first).Hours()) / 24
+			week = append(week, newDate(days, d.Hour(), d.Minute(), d.Second(), d.Nanosecond(), time.UTC))
+		}
+	}
+	return week
+}
@@ -0,0 +1,64 @@
+package openhours
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOpenHours_In(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	summer := time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		got  OpenHours
+		want OpenHours
+	}{
+		{"summer", NewMust("mo-fr 09:00-17:00", l).In(ny, summer), NewMust("mo-fr 04:00-12:00", ny)},
+		{"us dst only", NewMust("mo-fr 09:00-17:00", l).In(ny, time.Date(2019, 3, 20, 12, 0, 0, 0, time.UTC)), NewMust("mo-fr 05:00-13:00", ny)},
+		{"day before", NewMust("mo 01:00-03:00", l).In(ny, summer), NewMust("su 20:00-22:00", ny)},
+		{"past saturday", NewMust("sa 20:00-23:00", l).In(tokyo, summer), NewMust("su 04:00-07:00", tokyo)},
+		{"split", NewMust("sa 12:00-18:00", l).In(tokyo, summer), NewMust("sa 20:00-24:00; su 00:00-02:00", tokyo)},
+		{"dated", NewMust("jul 10:00-12:00", l).In(time.UTC, summer), NewMust("mo-sa 09:00-11:00", time.UTC)},
+		{"dated closed", NewMust("jul 10:00-12:00", l).In(time.UTC, time.Date(2019, 8, 14, 0, 0, 0, 0, time.UTC)), NewMust("su off", time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.got.Equal(tt.want) {
+				t.Errorf("OpenHours.In() = %v, want %v", tt.got.String(), tt.want.String())
+			}
+		})
+	}
+}
+
+func TestOpenHours_InPeriod(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tl := NewMust("mo-fr 09:00-17:00", l).InPeriod(ny, time.Date(2019, 3, 1, 0, 0, 0, 0, ny), time.Date(2019, 5, 1, 0, 0, 0, 0, ny))
+	if n := len(tl.Versions()); n != 3 {
+		t.Errorf("len(Timeline.Versions()) = %v, want 3", n)
+	}
+	tests := []struct {
+		args time.Time
+		want bool
+	}{
+		{time.Date(2019, 3, 5, 4, 30, 0, 0, ny), true},
+		{time.Date(2019, 3, 20, 4, 30, 0, 0, ny), false},
+		{time.Date(2019, 3, 20, 12, 30, 0, 0, ny), true},
+		{time.Date(2019, 4, 3, 4, 30, 0, 0, ny), true},
+		{time.Date(2019, 4, 3, 12, 30, 0, 0, ny), false},
+	}
+	for _, tt := range tests {
+		if got := tl.Match(tt.args); got != tt.want {
+			t.Errorf("Timeline.Match(%v) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}